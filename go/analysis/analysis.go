@@ -48,6 +48,20 @@ type Analyzer struct {
 	// serializable.
 	Run func(*Pass) (any, error)
 
+	// RunProgram, if non-nil, makes this a whole-program analyzer:
+	// one whose findings depend on all the packages at once, such
+	// as a report of unreachable functions.
+	//
+	// A driver that supports whole-program analysis calls
+	// RunProgram once, after Run has been applied to every package,
+	// passing a ProgramPass that provides the result and facts
+	// computed for each package. The go/analysis/checker driver,
+	// and thus multichecker, singlechecker, and analysistest,
+	// supports it. Drivers that analyze each package separately,
+	// such as unitchecker (used by 'go vet') and gopls, never call
+	// RunProgram, so they report only the diagnostics of Run.
+	RunProgram func(*ProgramPass) error
+
 	// RunDespiteErrors allows the driver to invoke
 	// the Run method of this analyzer even on a
 	// package that contains parse or type errors.
@@ -185,6 +199,58 @@ type ObjectFact struct {
 	Fact   Fact
 }
 
+// A ProgramPass provides information to the RunProgram function of a
+// whole-program analyzer about all the packages to which its Run
+// function was applied, and provides operations to it for reporting
+// diagnostics back to the driver.
+//
+// The RunProgram function should not call any of the ProgramPass
+// functions concurrently.
+type ProgramPass struct {
+	Analyzer *Analyzer // the identity of the current analyzer
+
+	Fset *token.FileSet // file position information
+
+	// Packages holds one element for each package to which Run was
+	// successfully applied, in dependency order: the initial
+	// packages and, if the analyzer uses facts, their dependencies.
+	Packages []*PackageResult
+
+	// Report reports a Diagnostic. Its position must lie within one
+	// of the initial packages (see PackageResult.Initial).
+	Report func(Diagnostic)
+
+	// AllPackageFacts returns a new slice containing all package
+	// facts of the analysis's FactTypes, for all packages, in
+	// unspecified order.
+	AllPackageFacts func() []PackageFact
+
+	// AllObjectFacts returns a new slice containing all object
+	// facts of the analysis's FactTypes, for all packages, in
+	// unspecified order.
+	AllObjectFacts func() []ObjectFact
+
+	/* Further fields may be added in future. */
+}
+
+// A PackageResult describes the application of a whole-program
+// analyzer's Run function to a single package.
+type PackageResult struct {
+	Pkg        *types.Package // type information about the package
+	Files      []*ast.File    // the abstract syntax tree of each file
+	TypesInfo  *types.Info    // type information about the syntax trees
+	TypesSizes types.Sizes    // function for computing sizes of types
+	Initial    bool           // whether the package was requested, not merely a dependency
+	Result     any            // the result of Run, of type Analyzer.ResultType
+}
+
+// Reportf is a helper function that reports a Diagnostic using the
+// specified position and formatted error message.
+func (pass *ProgramPass) Reportf(pos token.Pos, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	pass.Report(Diagnostic{Pos: pos, Message: msg})
+}
+
 // Reportf is a helper function that reports a Diagnostic using the
 // specified position and formatted error message.
 func (pass *Pass) Reportf(pos token.Pos, format string, args ...any) {
@@ -0,0 +1,214 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checker
+
+// This file defines baselines: records of diagnostics to be
+// disregarded by subsequent runs of the checker.
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// A Baseline records a set of diagnostics, typically those reported by
+// an earlier run, so that a subsequent run may report only new ones.
+// This allows analyzers to be adopted by a large code base without
+// first fixing all their existing findings.
+//
+// A diagnostic is identified by its analyzer, the path of its package,
+// the name of its enclosing package-level declaration (if any), and its
+// message, but not by its position, so that a baseline remains valid
+// as unrelated edits move diagnostics within their files.
+//
+// Use [Graph.Baseline] to create a Baseline, [Baseline.Write] to save
+// it, [ReadBaseline] to load it, and [Options.Baseline] to apply it.
+type Baseline struct {
+	counts map[baselineKey]int // number of occurrences of each diagnostic
+}
+
+// A baselineKey identifies a diagnostic independent of its position.
+type baselineKey struct {
+	Analyzer string `json:"analyzer"`
+	Package  string `json:"package"`
+	Decl     string `json:"decl,omitempty"` // name of enclosing declaration
+	Message  string `json:"message"`
+}
+
+// baselineFile is the JSON schema of a baseline file.
+type baselineFile struct {
+	Diagnostics []baselineEntry `json:"diagnostics"`
+}
+
+type baselineEntry struct {
+	baselineKey
+	Count int `json:"count"`
+}
+
+// Baseline returns a Baseline that records the diagnostics of the
+// root actions of the graph.
+//
+// Diagnostics reported at the same position in different variants
+// of a package (such as foo and foo.test) are recorded once.
+func (g *Graph) Baseline() *Baseline {
+	type key struct {
+		*analysis.Analyzer
+		posn    token.Position
+		message string
+	}
+	seen := make(map[key]bool)
+	b := &Baseline{counts: make(map[baselineKey]int)}
+	for _, act := range g.Roots {
+		if act.Err != nil {
+			continue
+		}
+		for _, diag := range act.Diagnostics {
+			k := key{act.Analyzer, act.Package.Fset.Position(diag.Pos), diag.Message}
+			if seen[k] {
+				continue // duplicate
+			}
+			seen[k] = true
+			b.counts[makeBaselineKey(act, diag)]++
+		}
+	}
+	return b
+}
+
+// ReadBaseline reads a Baseline in the form written by [Baseline.Write].
+func ReadBaseline(r io.Reader) (*Baseline, error) {
+	var file baselineFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("reading baseline: %v", err)
+	}
+	b := &Baseline{counts: make(map[baselineKey]int)}
+	for _, entry := range file.Diagnostics {
+		b.counts[entry.baselineKey] += entry.Count
+	}
+	return b, nil
+}
+
+// Write writes the baseline to w, in a JSON form whose entries are
+// sorted so that changes to it are easily reviewed.
+func (b *Baseline) Write(w io.Writer) error {
+	file := baselineFile{Diagnostics: []baselineEntry{}}
+	for k, count := range b.counts {
+		file.Diagnostics = append(file.Diagnostics, baselineEntry{k, count})
+	}
+	slices.SortFunc(file.Diagnostics, func(x, y baselineEntry) int {
+		return cmp.Or(
+			cmp.Compare(x.Package, y.Package),
+			cmp.Compare(x.Decl, y.Decl),
+			cmp.Compare(x.Analyzer, y.Analyzer),
+			cmp.Compare(x.Message, y.Message))
+	})
+	data, err := json.MarshalIndent(file, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// filter returns the diagnostics of act that are not recorded by the
+// baseline. If the baseline records n occurrences of a diagnostic,
+// the first n occurrences in act are omitted.
+func (b *Baseline) filter(act *Action) []analysis.Diagnostic {
+	var (
+		result []analysis.Diagnostic
+		used   = make(map[baselineKey]int)
+	)
+	for _, diag := range act.Diagnostics {
+		k := makeBaselineKey(act, diag)
+		if used[k] < b.counts[k] {
+			used[k]++
+			continue // baselined
+		}
+		result = append(result, diag)
+	}
+	return result
+}
+
+// makeBaselineKey returns the key of a diagnostic reported by act.
+func makeBaselineKey(act *Action, diag analysis.Diagnostic) baselineKey {
+	return baselineKey{
+		Analyzer: act.Analyzer.Name,
+		Package:  act.Package.PkgPath,
+		Decl:     enclosingDecl(act.Package, diag.Pos),
+		Message:  diag.Message,
+	}
+}
+
+// enclosingDecl returns the name of the package-level declaration or
+// spec of pkg that encloses pos, qualified by the receiver type for a
+// method (e.g. "T.m"), or "" if there is none.
+//
+// Unlike an [objectpath], this name does not depend on the order of
+// methods, and exists for unexported functions and variables too.
+func enclosingDecl(pkg *packages.Package, pos token.Pos) string {
+	for _, f := range pkg.Syntax {
+		if !(f.FileStart <= pos && pos <= f.FileEnd) {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if !(decl.Pos() <= pos && pos < decl.End()) {
+				continue
+			}
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv != nil && len(decl.Recv.List) > 0 {
+					if recv := receiverTypeName(decl.Recv.List[0].Type); recv != "" {
+						return recv + "." + decl.Name.Name
+					}
+				}
+				return decl.Name.Name
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if !(spec.Pos() <= pos && pos < spec.End()) {
+						continue
+					}
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						return spec.Name.Name
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.Name != "_" {
+								return name.Name
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// receiverTypeName returns the name of the named type of a method
+// receiver type expression such as *T or T[P], or "" if none.
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,459 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checker
+
+// This file defines the persistent cache of the outputs (facts and
+// diagnostics) of actions; see [Options.CacheDir].
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/objectpath"
+)
+
+// Entries of the cache that have not been used for cacheMaxAge are
+// deleted by a trim, performed at most once per cacheTrimInterval when
+// a cache is opened. To record use without writing the file system on
+// every cache hit, the modification time of an entry is updated only
+// if it is older than cacheMtimeInterval.
+const (
+	cacheMaxAge        = 5 * 24 * time.Hour
+	cacheTrimInterval  = 24 * time.Hour
+	cacheMtimeInterval = 1 * time.Hour
+)
+
+// An actionCache is a content-addressed store of the outputs of
+// actions, in a directory of the file system.
+type actionCache struct {
+	dir         string
+	exeHash     [sha256.Size]byte
+	packageKeys map[*packages.Package][sha256.Size]byte // immutable after construction
+}
+
+// newActionCache returns a cache in the specified directory for the
+// actions of the packages in the import graph of pkgs.
+func newActionCache(dir string, pkgs []*packages.Package) (*actionCache, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	cache := &actionCache{
+		dir:         dir,
+		packageKeys: make(map[*packages.Package][sha256.Size]byte),
+	}
+	h.Sum(cache.exeHash[:0])
+
+	// Compute the key of each package, in dependency order.
+	// A package whose files cannot be read has no key,
+	// nor have the packages that depend on it.
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if key, ok := cache.packageKey(pkg); ok {
+			cache.packageKeys[pkg] = key
+		}
+	})
+	cache.trim(time.Now())
+	return cache, nil
+}
+
+// trim deletes the entries of the cache that have not been used
+// recently, unless the cache was trimmed less than cacheTrimInterval
+// ago. The time of the last trim is the modification time of the file
+// trim.txt in the cache directory. Errors are ignored: a failed trim
+// merely leaves the cache larger than necessary.
+func (cache *actionCache) trim(now time.Time) {
+	trimFile := filepath.Join(cache.dir, "trim.txt")
+	if info, err := os.Stat(trimFile); err == nil && now.Sub(info.ModTime()) < cacheTrimInterval {
+		return // trimmed recently
+	}
+	subdirs, _ := os.ReadDir(cache.dir)
+	for _, subdir := range subdirs {
+		if !subdir.IsDir() {
+			continue
+		}
+		dir := filepath.Join(cache.dir, subdir.Name())
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			// Also delete temporary files abandoned by an interrupted store.
+			name := entry.Name()
+			if !strings.HasSuffix(name, "-a") && !strings.HasPrefix(name, "tmp-") {
+				continue
+			}
+			if info, err := entry.Info(); err == nil && now.Sub(info.ModTime()) > cacheMaxAge {
+				os.Remove(filepath.Join(dir, name)) // ignore error
+			}
+		}
+	}
+	if err := os.MkdirAll(cache.dir, 0777); err == nil {
+		os.WriteFile(trimFile, []byte(fmt.Sprintln(now.Unix())), 0666) // ignore error
+	}
+}
+
+// packageKey returns a hash of the inputs to the analysis of pkg:
+// its metadata and the content of its files, plus the keys of its
+// dependencies.
+func (cache *actionCache) packageKey(pkg *packages.Package) (key [sha256.Size]byte, ok bool) {
+	h := sha256.New()
+	fmt.Fprintf(h, "id %q path %q name %q illtyped %t\n", pkg.ID, pkg.PkgPath, pkg.Name, pkg.IllTyped)
+	if pkg.TypesSizes != nil {
+		fmt.Fprintf(h, "sizes %v\n", pkg.TypesSizes)
+	}
+	if mod := pkg.Module; mod != nil {
+		fmt.Fprintf(h, "module %q %q %q %t\n", mod.Path, mod.Version, mod.GoVersion, mod.Main)
+	}
+	for _, files := range [][]string{pkg.CompiledGoFiles, pkg.OtherFiles, pkg.IgnoredFiles} {
+		for _, filename := range files {
+			content, err := os.ReadFile(filename)
+			if err != nil {
+				return key, false
+			}
+			fmt.Fprintf(h, "file %q %x\n", filename, sha256.Sum256(content))
+		}
+	}
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths) // for determinism
+	for _, path := range paths {
+		depKey, ok := cache.packageKeys[pkg.Imports[path]]
+		if !ok {
+			return key, false
+		}
+		fmt.Fprintf(h, "import %q %x\n", path, depKey)
+	}
+	h.Sum(key[:0])
+	return key, true
+}
+
+// filename returns the name of the cache file for the outputs of act,
+// or "" if they cannot be cached.
+func (cache *actionCache) filename(act *Action) string {
+	pkgKey, ok := cache.packageKeys[act.Package]
+	if !ok {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "exe %x\nanalyzer %q\n", cache.exeHash, act.Analyzer.Name)
+	act.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(h, "flag %q %q\n", f.Name, f.Value)
+	})
+	fmt.Fprintf(h, "package %x\n", pkgKey)
+	name := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(cache.dir, name[:2], name+"-a")
+}
+
+// A cacheEntry holds the outputs of an action: the facts about its own
+// package, and its diagnostics.
+type cacheEntry struct {
+	ObjectFacts  []cachedObjectFact
+	PackageFacts []cachedFact
+	Diagnostics  []cachedDiagnostic
+}
+
+// A cachedFact is an encoded fact of one of the FactTypes of an
+// analyzer, identified by its index.
+type cachedFact struct {
+	Type int
+	Data []byte
+}
+
+type cachedObjectFact struct {
+	Object objectpath.Path
+	Fact   cachedFact
+}
+
+// A cachedPos is a position, as a file name and offset.
+// The zero value represents token.NoPos.
+type cachedPos struct {
+	File   string
+	Offset int
+}
+
+type cachedDiagnostic struct {
+	Pos, End       cachedPos
+	Category       string
+	Message        string
+	URL            string
+	SuggestedFixes []cachedSuggestedFix
+	Related        []cachedRelatedInformation
+}
+
+type cachedSuggestedFix struct {
+	Message   string
+	TextEdits []cachedTextEdit
+}
+
+type cachedTextEdit struct {
+	Pos, End cachedPos
+	NewText  []byte
+}
+
+type cachedRelatedInformation struct {
+	Pos, End cachedPos
+	Message  string
+}
+
+// load attempts to populate the facts and diagnostics of act from the
+// cache, after its vertical dependencies have been analyzed, and
+// reports whether it succeeded.
+func (cache *actionCache) load(act *Action) bool {
+	filename := cache.filename(act)
+	if filename == "" {
+		return false
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return false // miss
+	}
+	// Record the use of the entry, to protect it from trimming.
+	if info, err := os.Stat(filename); err == nil {
+		if now := time.Now(); now.Sub(info.ModTime()) > cacheMtimeInterval {
+			os.Chtimes(filename, now, now) // ignore error
+		}
+	}
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return false // corrupt
+	}
+
+	// Decode positions relative to the files of the package.
+	files := make(map[string]*token.File)
+	for _, f := range act.Package.Syntax {
+		tf := act.Package.Fset.File(f.FileStart)
+		files[tf.Name()] = tf
+	}
+	ok := true
+	decodePos := func(p cachedPos) token.Pos {
+		if p.File == "" {
+			return token.NoPos
+		}
+		tf := files[p.File]
+		if tf == nil || p.Offset > tf.Size() {
+			ok = false
+			return token.NoPos
+		}
+		return tf.Pos(p.Offset)
+	}
+	var diagnostics []analysis.Diagnostic
+	for _, d := range entry.Diagnostics {
+		diag := analysis.Diagnostic{
+			Pos:      decodePos(d.Pos),
+			End:      decodePos(d.End),
+			Category: d.Category,
+			Message:  d.Message,
+			URL:      d.URL,
+		}
+		for _, fix := range d.SuggestedFixes {
+			var edits []analysis.TextEdit
+			for _, edit := range fix.TextEdits {
+				edits = append(edits, analysis.TextEdit{
+					Pos:     decodePos(edit.Pos),
+					End:     decodePos(edit.End),
+					NewText: edit.NewText,
+				})
+			}
+			diag.SuggestedFixes = append(diag.SuggestedFixes, analysis.SuggestedFix{
+				Message:   fix.Message,
+				TextEdits: edits,
+			})
+		}
+		for _, rel := range d.Related {
+			diag.Related = append(diag.Related, analysis.RelatedInformation{
+				Pos:     decodePos(rel.Pos),
+				End:     decodePos(rel.End),
+				Message: rel.Message,
+			})
+		}
+		diagnostics = append(diagnostics, diag)
+	}
+
+	if !ok {
+		return false
+	}
+
+	// Decode the facts.
+	decodeFact := func(f cachedFact) (analysis.Fact, bool) {
+		if !(0 <= f.Type && f.Type < len(act.Analyzer.FactTypes)) {
+			return nil, false
+		}
+		typ := reflect.TypeOf(act.Analyzer.FactTypes[f.Type])
+		fact := reflect.New(typ.Elem()).Interface().(analysis.Fact)
+		if err := gob.NewDecoder(bytes.NewReader(f.Data)).Decode(fact); err != nil {
+			return nil, false
+		}
+		return fact, true
+	}
+	objectFacts := make(map[objectFactKey]analysis.Fact)
+	for _, f := range entry.ObjectFacts {
+		obj, err := objectpath.Object(act.Package.Types, f.Object)
+		if err != nil {
+			return false
+		}
+		fact, ok := decodeFact(f.Fact)
+		if !ok {
+			return false
+		}
+		objectFacts[objectFactKey{obj, factType(fact)}] = fact
+	}
+	packageFacts := make(map[packageFactKey]analysis.Fact)
+	for _, f := range entry.PackageFacts {
+		fact, ok := decodeFact(f)
+		if !ok {
+			return false
+		}
+		packageFacts[packageFactKey{act.Package.Types, factType(fact)}] = fact
+	}
+
+	// Inherit the facts of the dependencies, then add our own.
+	act.objectFacts = make(map[objectFactKey]analysis.Fact)
+	act.packageFacts = make(map[packageFactKey]analysis.Fact)
+	for _, dep := range act.Deps {
+		if dep.Package != act.Package {
+			inheritFacts(act, dep)
+		}
+	}
+	for k, fact := range objectFacts {
+		act.objectFacts[k] = fact
+	}
+	for k, fact := range packageFacts {
+		act.packageFacts[k] = fact
+	}
+	act.Diagnostics = diagnostics
+	return true
+}
+
+// store saves the facts and diagnostics of act in the cache, if
+// possible. Facts about objects that have no object path, which are
+// inaccessible to importing packages, are not saved.
+func (cache *actionCache) store(act *Action) {
+	filename := cache.filename(act)
+	if filename == "" {
+		return
+	}
+
+	// Encode positions relative to the files of the package.
+	files := make(map[*token.File]bool)
+	for _, f := range act.Package.Syntax {
+		files[act.Package.Fset.File(f.FileStart)] = true
+	}
+	ok := true
+	encodePos := func(pos token.Pos) cachedPos {
+		if !pos.IsValid() {
+			return cachedPos{}
+		}
+		tf := act.Package.Fset.File(pos)
+		if !files[tf] {
+			ok = false // e.g. a position in an assembly file
+			return cachedPos{}
+		}
+		return cachedPos{tf.Name(), tf.Offset(pos)}
+	}
+	var entry cacheEntry
+	for _, diag := range act.Diagnostics {
+		d := cachedDiagnostic{
+			Pos:      encodePos(diag.Pos),
+			End:      encodePos(diag.End),
+			Category: diag.Category,
+			Message:  diag.Message,
+			URL:      diag.URL,
+		}
+		for _, fix := range diag.SuggestedFixes {
+			var edits []cachedTextEdit
+			for _, edit := range fix.TextEdits {
+				edits = append(edits, cachedTextEdit{
+					Pos:     encodePos(edit.Pos),
+					End:     encodePos(edit.End),
+					NewText: edit.NewText,
+				})
+			}
+			d.SuggestedFixes = append(d.SuggestedFixes, cachedSuggestedFix{
+				Message:   fix.Message,
+				TextEdits: edits,
+			})
+		}
+		for _, rel := range diag.Related {
+			d.Related = append(d.Related, cachedRelatedInformation{
+				Pos:     encodePos(rel.Pos),
+				End:     encodePos(rel.End),
+				Message: rel.Message,
+			})
+		}
+		entry.Diagnostics = append(entry.Diagnostics, d)
+	}
+
+	// Encode the facts about this package.
+	encodeFact := func(fact analysis.Fact) cachedFact {
+		index := slices.IndexFunc(act.Analyzer.FactTypes, func(t analysis.Fact) bool {
+			return reflect.TypeOf(t) == reflect.TypeOf(fact)
+		})
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(fact); err != nil || index < 0 {
+			ok = false
+		}
+		return cachedFact{index, buf.Bytes()}
+	}
+	var enc objectpath.Encoder
+	for k, fact := range act.objectFacts {
+		if k.obj.Pkg() != act.Package.Types {
+			continue // inherited
+		}
+		path, err := enc.For(k.obj)
+		if err != nil {
+			continue // inaccessible
+		}
+		entry.ObjectFacts = append(entry.ObjectFacts, cachedObjectFact{path, encodeFact(fact)})
+	}
+	for k, fact := range act.packageFacts {
+		if k.pkg == act.Package.Types {
+			entry.PackageFacts = append(entry.PackageFacts, encodeFact(fact))
+		}
+	}
+	if !ok {
+		return
+	}
+
+	// Write the file atomically.
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(buf.Bytes())
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), filename) != nil {
+		os.Remove(tmp.Name()) // ignore error
+	}
+}
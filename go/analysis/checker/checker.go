@@ -38,6 +38,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -56,6 +57,26 @@ type Options struct {
 	SanityCheck bool      // check fact encoding is ok and deterministic
 	FactLog     io.Writer // if non-nil, log each exported fact to it
 
+	// Baseline, if non-nil, records diagnostics that are omitted
+	// from the Diagnostics of the root actions of the graph.
+	Baseline *Baseline
+
+	// CacheDir, if non-empty, is a directory in which to cache
+	// the facts and diagnostics of each action, keyed by the
+	// analyzer (including the executable that defines it and its
+	// flags) and the content of the package and its dependencies.
+	// An action whose outputs are found in the cache is not
+	// executed, nor are the actions whose results only it requires,
+	// so that repeated runs reanalyze only changed packages.
+	// An action whose Result is required by another action is
+	// never cached, and the Result of a root action loaded from
+	// the cache is nil.
+	//
+	// Entries not used for five days are deleted when the
+	// cache is opened, at most once a day, so the directory
+	// does not grow without bound; it may be deleted at any time.
+	CacheDir string
+
 	// TODO(adonovan): expose ReadFile so that an Overlay specified
 	// in the [packages.Config] can be communicated via
 	// Pass.ReadFile to each Analyzer.
@@ -123,6 +144,8 @@ type Action struct {
 	Duration    time.Duration // execution time of this step
 
 	opts         *Options
+	cache        *actionCache // nil unless Options.CacheDir
+	resultNeeded bool         // whether Result is required by another action or RunProgram
 	once         sync.Once
 	pass         *analysis.Pass
 	objectFacts  map[objectFactKey]analysis.Fact
@@ -164,16 +187,28 @@ func Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Opt
 	}
 	actions := make(map[key]*Action)
 
+	var cache *actionCache
+	if opts.CacheDir != "" {
+		var err error
+		cache, err = newActionCache(opts.CacheDir, pkgs)
+		if err != nil {
+			return nil, fmt.Errorf("can't use cache: %v", err)
+		}
+	}
+
 	var mkAction func(a *analysis.Analyzer, pkg *packages.Package) *Action
 	mkAction = func(a *analysis.Analyzer, pkg *packages.Package) *Action {
 		k := key{a, pkg}
 		act, ok := actions[k]
 		if !ok {
-			act = &Action{Analyzer: a, Package: pkg, opts: opts}
+			act = &Action{Analyzer: a, Package: pkg, opts: opts, cache: cache}
+			act.resultNeeded = a.RunProgram != nil
 
 			// Add a dependency on each required analyzers.
 			for _, req := range a.Requires {
-				act.Deps = append(act.Deps, mkAction(req, pkg))
+				dep := mkAction(req, pkg)
+				dep.resultNeeded = true
+				act.Deps = append(act.Deps, dep)
 			}
 
 			// An analysis that consumes/produces facts
@@ -208,6 +243,22 @@ func Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Opt
 	// Execute the graph in parallel.
 	execAll(roots)
 
+	// Apply each whole-program analyzer to its results.
+	seen := make(map[*analysis.Analyzer]bool)
+	for _, a := range analyzers {
+		if a.RunProgram != nil && !seen[a] {
+			seen[a] = true
+			runProgram(a, roots)
+		}
+	}
+
+	// Omit baselined diagnostics.
+	if opts.Baseline != nil {
+		for _, root := range roots {
+			root.Diagnostics = opts.Baseline.filter(root)
+		}
+	}
+
 	// Ensure that only root Results are visible to caller.
 	// (The others are considered temporary intermediaries.)
 	// TODO(adonovan): opt: clear them earlier, so we can
@@ -258,6 +309,28 @@ func execAll(actions []*Action) {
 func (act *Action) exec() { act.once.Do(act.execOnce) }
 
 func (act *Action) execOnce() {
+	// If the outputs of the action may be cached, analyze only
+	// the vertical dependencies (for facts) before consulting
+	// the cache: the others (for results) are needed only upon
+	// a miss.
+	cacheable := act.cache != nil && !act.resultNeeded
+	if cacheable {
+		var vertical []*Action
+		for _, dep := range act.Deps {
+			if dep.Package != act.Package {
+				vertical = append(vertical, dep)
+			}
+		}
+		execAll(vertical)
+		if !slices.ContainsFunc(vertical, func(dep *Action) bool { return dep.Err != nil }) {
+			t0 := time.Now()
+			if act.cache.load(act) {
+				act.Duration = time.Since(t0)
+				return // hit
+			}
+		}
+	}
+
 	// Analyze dependencies.
 	execAll(act.Deps)
 
@@ -366,13 +439,12 @@ func (act *Action) execOnce() {
 				pass.Pkg.Path(), pass.Analyzer, got, want)
 		}
 
-		// resolve diagnostic URLs
-		for i := range act.Diagnostics {
-			url, err := driverutil.ResolveURL(act.Analyzer, act.Diagnostics[i])
-			if err != nil {
+		// The diagnostics of a whole-program analyzer
+		// are finished after RunProgram (see runProgram).
+		if pass.Analyzer.RunProgram == nil {
+			if err := act.finishDiagnostics(); err != nil {
 				return nil, err
 			}
-			act.Diagnostics[i].URL = url
 		}
 		return result, nil
 	}()
@@ -380,6 +452,25 @@ func (act *Action) execOnce() {
 	// Help detect (disallowed) calls after Run.
 	pass.ExportObjectFact = nil
 	pass.ExportPackageFact = nil
+
+	if cacheable && act.Err == nil {
+		act.cache.store(act)
+	}
+}
+
+// finishDiagnostics applies the //lint:ignore directives of the
+// package to the diagnostics of the action, and resolves their URLs.
+func (act *Action) finishDiagnostics() error {
+	act.Diagnostics = driverutil.SuppressDiagnostics(act.Package.Fset, act.Package.Syntax, act.Analyzer, act.Diagnostics)
+
+	for i := range act.Diagnostics {
+		url, err := driverutil.ResolveURL(act.Analyzer, act.Diagnostics[i])
+		if err != nil {
+			return err
+		}
+		act.Diagnostics[i].URL = url
+	}
+	return nil
 }
 
 // inheritFacts populates act.facts with
@@ -5,12 +5,20 @@
 package checker_test
 
 import (
+	"fmt"
+	"go/ast"
+	"go/types"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
+	"time"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/internal/testenv"
 	"golang.org/x/tools/txtar"
@@ -87,3 +95,304 @@ package hello
 		t.Errorf("Pass.Module.GoVersion = %q, want %q", got.GoVersion, "1.13")
 	}
 }
+
+// TestCache checks that, with Options.CacheDir, facts and diagnostics
+// are loaded from the cache and that only changed packages (and those
+// that depend on them) are reanalyzed.
+func TestCache(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	const src = `
+-- go.mod --
+module example.com
+go 1.22
+
+-- a/a.go --
+package a
+
+func Bad() {}
+
+-- b/b.go --
+package b
+
+import "example.com/a"
+
+func _() { a.Bad() }
+`
+	dir := t.TempDir()
+	fs, err := txtar.FS(txtar.Parse([]byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.CopyFS(dir, fs); err != nil {
+		t.Fatal(err)
+	}
+
+	// The analyzer marks functions named Bad with a fact,
+	// and reports calls to marked functions.
+	var ran []string // packages analyzed
+	badAnalyzer := &analysis.Analyzer{
+		Name:      "bad",
+		Doc:       "reports calls to functions named Bad",
+		FactTypes: []analysis.Fact{new(isBad)},
+		Requires:  []*analysis.Analyzer{inspect.Analyzer}, // (not run upon a cache hit)
+		Run: func(pass *analysis.Pass) (any, error) {
+			ran = append(ran, pass.Pkg.Path())
+			if obj := pass.Pkg.Scope().Lookup("Bad"); obj != nil {
+				pass.ExportObjectFact(obj, &isBad{obj.Name()})
+			}
+			inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+			for n := range inspect.PreorderSeq((*ast.SelectorExpr)(nil)) {
+				sel := n.(*ast.SelectorExpr)
+				if obj := pass.TypesInfo.Uses[sel.Sel]; obj != nil && pass.ImportObjectFact(obj, new(isBad)) {
+					pass.Reportf(sel.Pos(), "call to bad function %s", obj.Name())
+				}
+			}
+			return nil, nil
+		},
+	}
+
+	run := func() (diags []string) {
+		ran = nil
+		cfg := &packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}
+		pkgs, err := packages.Load(cfg, "example.com/b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := &checker.Options{CacheDir: filepath.Join(dir, "cache"), Sequential: true}
+		graph, err := checker.Analyze([]*analysis.Analyzer{badAnalyzer}, pkgs, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, act := range graph.Roots {
+			if act.Err != nil {
+				t.Fatal(act.Err)
+			}
+			for _, diag := range act.Diagnostics {
+				diags = append(diags, fmt.Sprintf("%s: %s", act.Package.Fset.Position(diag.Pos), diag.Message))
+			}
+		}
+		return diags
+	}
+
+	first := run()
+	if want := []string{"example.com/a", "example.com/b"}; !slices.Equal(ran, want) {
+		t.Errorf("first run analyzed %v, want %v", ran, want)
+	}
+	if len(first) != 1 {
+		t.Fatalf("first run reported %v, want one diagnostic", first)
+	}
+
+	// Nothing has changed, so nothing is reanalyzed.
+	if second := run(); !slices.Equal(second, first) {
+		t.Errorf("second run reported %v, want %v", second, first)
+	}
+	if len(ran) > 0 {
+		t.Errorf("second run analyzed %v, want none", ran)
+	}
+
+	// Only the changed package is reanalyzed,
+	// using the facts of its dependency from the cache.
+	bfile := filepath.Join(dir, "b", "b.go")
+	if err := os.WriteFile(bfile, []byte("package b\n\nimport \"example.com/a\"\n\nvar _ = 1\n\nfunc _() { a.Bad() }\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	third := run()
+	if want := []string{"example.com/b"}; !slices.Equal(ran, want) {
+		t.Errorf("third run analyzed %v, want %v", ran, want)
+	}
+	if want := []string{bfile + ":7:12: call to bad function Bad"}; !slices.Equal(third, want) {
+		t.Errorf("third run reported %v, want %v", third, want)
+	}
+}
+
+// TestCacheTrim checks that opening the cache deletes entries that
+// have not been used recently, but only once per trim interval.
+func TestCacheTrim(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	const src = `
+-- go.mod --
+module example.com
+go 1.22
+
+-- a/a.go --
+package a
+`
+	dir := t.TempDir()
+	fs, err := txtar.FS(txtar.Parse([]byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.CopyFS(dir, fs); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &packages.Config{Mode: packages.LoadSyntax, Dir: dir}
+	pkgs, err := packages.Load(cfg, "example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop := &analysis.Analyzer{
+		Name: "noop",
+		Doc:  "does nothing",
+		Run:  func(*analysis.Pass) (any, error) { return nil, nil },
+	}
+
+	// Populate the cache with a stale entry and a recent one,
+	// and mark the last trim as long ago.
+	cacheDir := filepath.Join(dir, "cache")
+	long := time.Now().Add(-30 * 24 * time.Hour)
+	write := func(name string, mtime time.Time) string {
+		filename := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filename, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	stale := write("00/00stale-a", long)
+	recent := write("00/00recent-a", time.Now())
+	write("trim.txt", long)
+
+	analyze := func() {
+		opts := &checker.Options{CacheDir: cacheDir, Sequential: true}
+		if _, err := checker.Analyze([]*analysis.Analyzer{noop}, pkgs, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exists := func(filename string) bool {
+		_, err := os.Stat(filename)
+		return err == nil
+	}
+
+	analyze()
+	if exists(stale) {
+		t.Errorf("stale cache entry was not deleted")
+	}
+	if !exists(recent) {
+		t.Errorf("recent cache entry was deleted")
+	}
+
+	// The cache was just trimmed, so it is not trimmed again.
+	stale = write("00/00stale-a", long)
+	analyze()
+	if !exists(stale) {
+		t.Errorf("cache was trimmed twice within the trim interval")
+	}
+}
+
+type isBad struct{ Name string } // (facts must be gob-encodable)
+
+func (*isBad) AFact()         {}
+func (*isBad) String() string { return "isBad" }
+
+// TestRunProgram checks that the RunProgram function of a whole-program
+// analyzer observes the results of all packages, and that its
+// diagnostics are added to the root actions and subject to
+// //lint:ignore directives.
+func TestRunProgram(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	const src = `
+-- go.mod --
+module example.com
+go 1.22
+
+-- a/a.go --
+package a
+
+func Used() {}
+
+func Unused() {}
+
+//lint:ignore unused kept for reflection
+func Ignored() {}
+
+-- b/b.go --
+package b
+
+import "example.com/a"
+
+func main() { a.Used() }
+`
+	dir := t.TempDir()
+	fs, err := txtar.FS(txtar.Parse([]byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.CopyFS(dir, fs); err != nil {
+		t.Fatal(err)
+	}
+
+	// The analyzer records the functions declared and used by each
+	// package, and reports those declared but used by no package.
+	type funcs struct{ decls, uses []*types.Func }
+	var pkgs []string // packages observed by RunProgram
+	unusedAnalyzer := &analysis.Analyzer{
+		Name:       "unused",
+		Doc:        "reports functions not used by any package",
+		ResultType: reflect.TypeFor[*funcs](),
+		Run: func(pass *analysis.Pass) (any, error) {
+			res := new(funcs)
+			for id, obj := range pass.TypesInfo.Defs {
+				if fn, ok := obj.(*types.Func); ok && id.Name != "main" {
+					res.decls = append(res.decls, fn)
+				}
+			}
+			for _, obj := range pass.TypesInfo.Uses {
+				if fn, ok := obj.(*types.Func); ok {
+					res.uses = append(res.uses, fn)
+				}
+			}
+			return res, nil
+		},
+		RunProgram: func(pass *analysis.ProgramPass) error {
+			used := make(map[*types.Func]bool)
+			for _, pkg := range pass.Packages {
+				pkgs = append(pkgs, pkg.Pkg.Path())
+				for _, fn := range pkg.Result.(*funcs).uses {
+					used[fn] = true
+				}
+			}
+			for _, pkg := range pass.Packages {
+				for _, fn := range pkg.Result.(*funcs).decls {
+					if pkg.Initial && !used[fn] {
+						pass.Reportf(fn.Pos(), "function %s is never used", fn.Name())
+					}
+				}
+			}
+			return nil
+		},
+	}
+
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}
+	initial, err := packages.Load(cfg, "example.com/b", "example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{unusedAnalyzer}, initial, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			t.Fatal(act.Err)
+		}
+		for _, diag := range act.Diagnostics {
+			got = append(got, fmt.Sprintf("%s: %s: %s", act.Package.PkgPath, act.Package.Fset.Position(diag.Pos), diag.Message))
+		}
+	}
+	if want := []string{"example.com/a", "example.com/b"}; !slices.Equal(pkgs, want) {
+		t.Errorf("RunProgram observed packages %v, want %v", pkgs, want)
+	}
+	afile := filepath.Join(dir, "a", "a.go")
+	if want := []string{"example.com/a: " + afile + ":5:6: function Unused is never used"}; !slices.Equal(got, want) {
+		t.Errorf("got diagnostics %v, want %v", got, want)
+	}
+}
@@ -86,3 +86,25 @@ func writeJSONDiagnostics(w io.Writer, roots []*Action) error {
 	})
 	return tree.Print(w)
 }
+
+// PrintSARIF emits diagnostics in SARIF 2.1.0 form to w, with one run
+// per analyzer. Diagnostics are shown only for the root nodes,
+// but errors (if any) are shown for all dependencies.
+func (g *Graph) PrintSARIF(w io.Writer) error {
+	return writeSARIFDiagnostics(w, g.Roots)
+}
+
+func writeSARIFDiagnostics(w io.Writer, roots []*Action) error {
+	var sarif driverutil.SARIFLog
+	forEach(roots, func(act *Action) error {
+		if act.IsRoot || act.Err != nil {
+			var diags []analysis.Diagnostic
+			if act.IsRoot {
+				diags = act.Diagnostics
+			}
+			sarif.Add(act.Package.Fset, act.Package.ID, act.Analyzer, diags, act.Err)
+		}
+		return nil
+	})
+	return sarif.Print(w)
+}
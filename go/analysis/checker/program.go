@@ -0,0 +1,138 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checker
+
+// This file defines the final step of whole-program analyzers,
+// those with an [analysis.Analyzer.RunProgram] function.
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/internal/analysis/driverutil"
+)
+
+// runProgram applies the RunProgram function of analyzer a to the
+// results of its actions in the graph, all of which have executed,
+// and adds the diagnostics it reports to the root actions of a.
+//
+// If RunProgram fails, or is not called because some action of a
+// failed, the error is recorded in each root action of a.
+func runProgram(a *analysis.Analyzer, roots []*Action) {
+	var aroots []*Action // root actions of a
+	for _, root := range roots {
+		if root.Analyzer == a {
+			aroots = append(aroots, root)
+		}
+	}
+	if len(aroots) == 0 {
+		return // no packages
+	}
+	setErr := func(err error) {
+		for _, root := range aroots {
+			if root.Err == nil {
+				root.Err = err
+			}
+		}
+	}
+
+	// Gather all actions of a, in dependency order.
+	// The analysis of an incomplete program would
+	// be unsound, so give up if any action failed.
+	var (
+		acts   []*Action
+		failed []string
+	)
+	forEach(aroots, func(act *Action) error {
+		if act.Analyzer == a {
+			acts = append(acts, act)
+			if act.Err != nil {
+				failed = append(failed, act.String())
+			}
+		}
+		return nil
+	})
+	if failed != nil {
+		sort.Strings(failed)
+		setErr(fmt.Errorf("whole-program analysis skipped due to failed prerequisites: %s", strings.Join(failed, ", ")))
+		return
+	}
+
+	// Each diagnostic is added to the (first) root
+	// action whose package contains its file.
+	fset := aroots[0].Package.Fset
+	fileRoots := make(map[*token.File]*Action)
+	for _, root := range aroots {
+		for _, f := range root.Package.Syntax {
+			tok := fset.File(f.FileStart)
+			if _, ok := fileRoots[tok]; !ok {
+				fileRoots[tok] = root
+			}
+		}
+	}
+
+	pass := &analysis.ProgramPass{
+		Analyzer: a,
+		Fset:     fset,
+		Report: func(d analysis.Diagnostic) {
+			root, ok := fileRoots[fset.File(d.Pos)]
+			if !ok {
+				panic(fmt.Sprintf("%s: RunProgram reported a diagnostic at %s, outside the initial packages",
+					a, fset.Position(d.Pos)))
+			}
+			// Assert that SuggestedFixes are well formed.
+			if err := driverutil.ValidateFixes(fset, a, d.SuggestedFixes); err != nil {
+				panic(err)
+			}
+			root.Diagnostics = append(root.Diagnostics, d)
+		},
+		AllPackageFacts: func() []analysis.PackageFact {
+			var facts []analysis.PackageFact
+			for _, act := range acts {
+				for k, fact := range act.packageFacts {
+					if k.pkg == act.Package.Types { // (not inherited)
+						facts = append(facts, analysis.PackageFact{Package: k.pkg, Fact: fact})
+					}
+				}
+			}
+			return facts
+		},
+		AllObjectFacts: func() []analysis.ObjectFact {
+			var facts []analysis.ObjectFact
+			for _, act := range acts {
+				for k, fact := range act.objectFacts {
+					if k.obj.Pkg() == act.Package.Types { // (not inherited)
+						facts = append(facts, analysis.ObjectFact{Object: k.obj, Fact: fact})
+					}
+				}
+			}
+			return facts
+		},
+	}
+	for _, act := range acts {
+		pass.Packages = append(pass.Packages, &analysis.PackageResult{
+			Pkg:        act.Package.Types,
+			Files:      act.Package.Syntax,
+			TypesInfo:  act.Package.TypesInfo,
+			TypesSizes: act.Package.TypesSizes,
+			Initial:    act.IsRoot,
+			Result:     act.Result,
+		})
+	}
+
+	if err := a.RunProgram(pass); err != nil {
+		setErr(err)
+		return
+	}
+
+	for _, root := range aroots {
+		if err := root.finishDiagnostics(); err != nil {
+			root.Err = err
+		}
+	}
+}
@@ -79,6 +79,7 @@ The [Analyzer] type has more fields besides those shown above:
 		Doc              string
 		Flags            flag.FlagSet
 		Run              func(*Pass) (interface{}, error)
+		RunProgram       func(*ProgramPass) error
 		RunDespiteErrors bool
 		ResultType       reflect.Type
 		Requires         []*Analyzer
@@ -112,6 +113,14 @@ Finally, the Run field contains a function to be called by the driver to
 execute the analysis on a single package. The driver passes it an
 instance of the Pass type.
 
+A few checks, such as reporting functions that are unreachable from
+any main package, cannot be expressed one package at a time. For these,
+the optional RunProgram field contains a function to be called once,
+after Run has been applied to every package, with a [ProgramPass]
+holding the result and facts computed for each one. Only drivers that
+analyze the whole program in a single process, such as multichecker,
+call RunProgram.
+
 # Pass
 
 A [Pass] describes a single unit of work: the application of a particular
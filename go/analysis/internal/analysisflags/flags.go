@@ -3,7 +3,7 @@
 // license that can be found in the LICENSE file.
 
 // Package analysisflags defines helpers for processing flags (-help,
-// -json, -fix, -diff, etc) common to unitchecker and
+// -json, -sarif, -fix, -diff, etc) common to unitchecker and
 // {single,multi}checker. It is not intended for broader use.
 package analysisflags
 
@@ -24,6 +24,7 @@ import (
 // flags common to all {single,multi,unit}checkers.
 var (
 	JSON    = false // -json
+	SARIF   = false // -sarif
 	Context = -1    // -c=N: if N>0, display offending line plus N lines of context
 	Fix     bool    // -fix
 	Diff    bool    // -diff
@@ -74,6 +75,7 @@ func Parse(analyzers []*analysis.Analyzer, multi bool) []*analysis.Analyzer {
 
 	// flags common to all checkers
 	flag.BoolVar(&JSON, "json", JSON, "emit JSON output")
+	flag.BoolVar(&SARIF, "sarif", SARIF, "emit SARIF 2.1.0 output (not supported by go vet)")
 	flag.IntVar(&Context, "c", Context, `display offending line with this many lines of context`)
 	flag.BoolVar(&Fix, "fix", false, "apply all suggested fixes")
 	flag.BoolVar(&Diff, "diff", false, "with -fix, don't update the files, but print a unified diff")
@@ -93,6 +95,10 @@ func Parse(analyzers []*analysis.Analyzer, multi bool) []*analysis.Analyzer {
 
 	flag.Parse() // (ExitOnError)
 
+	if JSON && SARIF {
+		log.Fatalf("-json and -sarif are mutually exclusive")
+	}
+
 	// -flags: print flags so that go vet knows which ones are legitimate.
 	if *printflags {
 		printFlags()
@@ -171,9 +177,9 @@ func printFlags() {
 	flag.VisitAll(func(f *flag.Flag) {
 		// Don't report {single,multi}checker debugging
 		// flags or fix as these have no effect on unitchecker
-		// (as invoked by 'go vet').
+		// (as invoked by 'go vet'), nor sarif, which it rejects.
 		switch f.Name {
-		case "debug", "cpuprofile", "memprofile", "trace", "fix":
+		case "debug", "cpuprofile", "memprofile", "trace", "fix", "baseline", "write-baseline", "cache", "sarif":
 			return
 		}
 
@@ -14,6 +14,7 @@ package checker
 // TODO(adonovan): publish the JSON schema in go/analysis or analysisjson.
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"io"
@@ -51,6 +52,15 @@ var (
 
 	// IncludeTests indicates whether test files should be analyzed too.
 	IncludeTests = true
+
+	// Baseline is the name of a file of diagnostics to omit (-baseline),
+	// or, if WriteBaseline (-write-baseline), to record.
+	Baseline      string
+	WriteBaseline bool
+
+	// CacheDir is the directory of the persistent cache of facts
+	// and diagnostics (-cache), if any.
+	CacheDir string
 )
 
 // RegisterFlags registers command-line flags used by the analysis driver.
@@ -64,6 +74,9 @@ func RegisterFlags() {
 	flag.StringVar(&MemProfile, "memprofile", "", "write memory profile to this file")
 	flag.StringVar(&Trace, "trace", "", "write trace log to this file")
 	flag.BoolVar(&IncludeTests, "test", IncludeTests, "indicates whether test files should be analyzed, too")
+	flag.StringVar(&Baseline, "baseline", "", "omit the diagnostics recorded in this baseline file")
+	flag.BoolVar(&WriteBaseline, "write-baseline", false, "with -baseline, don't print diagnostics, but record them in the baseline file")
+	flag.StringVar(&CacheDir, "cache", "", "cache facts and diagnostics in this directory, to reanalyze only changed packages")
 }
 
 // Run loads the packages specified by args using go/packages,
@@ -179,6 +192,31 @@ func Run(args []string, analyzers []*analysis.Analyzer) (exitcode int) {
 		Sequential:  dbg('p'),
 		FactLog:     factLog,
 	}
+	if !analysisflags.Fix {
+		// Fixes require the Pass of each root action,
+		// which is not available after a cache hit.
+		opts.CacheDir = CacheDir
+	}
+	if WriteBaseline && Baseline == "" {
+		log.Print("-write-baseline requires -baseline=file")
+		exitAtLeast(1)
+		return
+	}
+	if Baseline != "" && !WriteBaseline {
+		f, err := os.Open(Baseline)
+		if err != nil {
+			log.Print(err)
+			exitAtLeast(1)
+			return
+		}
+		opts.Baseline, err = checker.ReadBaseline(f)
+		f.Close()
+		if err != nil {
+			log.Printf("%s: %v", Baseline, err)
+			exitAtLeast(1)
+			return
+		}
+	}
 	if dbg('v') {
 		log.Printf("building graph of analysis passes")
 	}
@@ -189,6 +227,22 @@ func Run(args []string, analyzers []*analysis.Analyzer) (exitcode int) {
 		return
 	}
 
+	// Don't print the diagnostics,
+	// but record them in the baseline file.
+	if WriteBaseline {
+		var buf bytes.Buffer
+		if err := graph.Baseline().Write(&buf); err != nil {
+			log.Print(err)
+			exitAtLeast(1)
+			return
+		}
+		if err := os.WriteFile(Baseline, buf.Bytes(), 0644); err != nil {
+			log.Print(err)
+			exitAtLeast(1)
+		}
+		return
+	}
+
 	// Don't print the diagnostics,
 	// but apply all fixes from the root actions.
 	if analysisflags.Fix {
@@ -214,7 +268,7 @@ func Run(args []string, analyzers []*analysis.Analyzer) (exitcode int) {
 			exitAtLeast(1)
 			return
 		}
-		// Don't proceed to print text/JSON,
+		// Don't proceed to print text/JSON/SARIF,
 		// and don't report an error
 		// just because there were diagnostics.
 		return
@@ -229,18 +283,22 @@ func Run(args []string, analyzers []*analysis.Analyzer) (exitcode int) {
 	return
 }
 
-// printDiagnostics prints diagnostics in text or JSON form
+// printDiagnostics prints diagnostics in text, JSON, or SARIF form
 // and returns the appropriate exit code.
 func printDiagnostics(graph *checker.Graph) (exitcode int) {
 	// Keep consistent with analogous logic in
 	// processResults in ../../unitchecker/unitchecker.go.
 
 	// Print the results.
-	// With -json, the exit code is always zero.
+	// With -json or -sarif, the exit code is always zero.
 	if analysisflags.JSON {
 		if err := graph.PrintJSON(os.Stdout); err != nil {
 			return 1
 		}
+	} else if analysisflags.SARIF {
+		if err := graph.PrintSARIF(os.Stdout); err != nil {
+			return 1
+		}
 	} else {
 		if err := graph.PrintText(os.Stderr, analysisflags.Context); err != nil {
 			return 1
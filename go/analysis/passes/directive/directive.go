@@ -15,6 +15,7 @@ import (
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/internal/analysis/analyzerutil"
+	"golang.org/x/tools/internal/analysis/driverutil"
 )
 
 const Doc = `check Go toolchain directives such as //go:debug
@@ -27,6 +28,18 @@ For //go:debug (see https://go.dev/doc/godebug), the analyzer checks
 that the directives are placed only in Go source files, only above the
 package comment, and only in package main or *_test.go files.
 
+The analyzer also checks the syntax of //lint:ignore directives,
+which suppress the diagnostics of the named analyzers on the line of
+the directive and, unless it follows code on that line, the following
+line, or, in the doc comment of a declaration, within that declaration:
+
+	//lint:ignore analyzer[,analyzer...] reason
+
+Drivers such as 'go vet' and gopls report a //lint:ignore directive
+that suppresses no diagnostics as a diagnostic of the named analyzer.
+Names of analyzers unknown to the driver are ignored, so that the same
+directives can serve several drivers.
+
 Support for other known directives may be added in the future.
 
 This analyzer does not check //go:build, which is handled by the
@@ -79,6 +92,9 @@ func checkGoFile(pass *analysis.Pass, f *ast.File) {
 		// Check each line of a //-comment.
 		for _, c := range group.List {
 			check.comment(c.Slash, c.Text)
+			if _, _, ok, err := driverutil.ParseIgnoreDirective(c.Text); ok && err != nil {
+				pass.Reportf(c.Slash, "%v", err)
+			}
 		}
 	}
 }
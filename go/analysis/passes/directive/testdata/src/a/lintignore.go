@@ -0,0 +1,19 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p
+
+func _() {
+	// want +1 `//lint:ignore directive lacks an analyzer name`
+	//lint:ignore
+
+	// want +1 `//lint:ignore directive lacks a reason`
+	//lint:ignore printf
+
+	// want +1 `//lint:ignore directive has an empty analyzer name`
+	//lint:ignore printf,,shadow reason
+
+	//lint:ignore printf,shadow reason
+	//lint:ignored not a directive
+}
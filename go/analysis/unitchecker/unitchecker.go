@@ -92,6 +92,9 @@ type Config struct {
 //	-fix		don't print each diagnostic, apply its first fix
 //	-diff		don't apply a fix, print the diff (requires -fix)
 //	-json		print diagnostics and fixes in JSON form
+//
+// The -sarif flag of the other drivers is not supported, as a SARIF
+// log describes a whole run of the tool, not a single package.
 func Main(analyzers ...*analysis.Analyzer) {
 	progname := filepath.Base(os.Args[0])
 	log.SetFlags(0)
@@ -132,6 +135,12 @@ Usage of %[1]s:
 // and calls os.Exit with an appropriate error code.
 // It assumes flags have already been set.
 func Run(configFile string, analyzers []*analysis.Analyzer) {
+	if analysisflags.SARIF {
+		// Each invocation analyzes one package, so the
+		// SARIF logs of a go vet run would be concatenated.
+		log.Fatal("-sarif is not supported when analyzing a single unit; use a checker-based driver such as multichecker")
+	}
+
 	cfg, err := readConfig(configFile)
 	if err != nil {
 		log.Fatal(err)
@@ -491,7 +500,8 @@ func run(fset *token.FileSet, cfg *Config, analyzers []*analysis.Analyzer) ([]re
 			t0 := time.Now()
 			act.result, act.err = a.Run(pass)
 
-			if act.err == nil { // resolve URLs on diagnostics.
+			if act.err == nil { // honor //lint:ignore directives and resolve URLs on diagnostics.
+				act.diagnostics = driverutil.SuppressDiagnostics(fset, files, a, act.diagnostics)
 				for i := range act.diagnostics {
 					if url, uerr := driverutil.ResolveURL(a, act.diagnostics[i]); uerr == nil {
 						act.diagnostics[i].URL = url
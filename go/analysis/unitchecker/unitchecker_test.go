@@ -248,6 +248,28 @@ var _ = fmt.Sprintf(msg)
 		json := parseJSON(t, stdout)
 		substring(t, "json", json, "c/c.go:5:5: [assign@golang.org/fake/c] self-assignment of i")
 	})
+	t.Run("sarif", func(t *testing.T) {
+		// go vet does not offer -sarif, which unitchecker rejects,
+		// as it would print one SARIF log per package.
+		minivet := func(args ...string) (string, error) {
+			cmd := exec.Command(os.Args[0], args...)
+			cmd.Env = append(os.Environ(), "ENTRYPOINT=minivet")
+			out, err := cmd.CombinedOutput()
+			return string(out), err
+		}
+		flags, err := minivet("-flags")
+		if err != nil {
+			t.Fatalf("-flags failed: %v\n%s", err, flags)
+		}
+		if strings.Contains(flags, `"sarif"`) {
+			t.Errorf("-flags reported -sarif:\n%s", flags)
+		}
+		out, err := minivet("-sarif", filepath.Join(tmpdir, "unit.cfg"))
+		if err == nil {
+			t.Fatalf("-sarif succeeded:\n%s", out)
+		}
+		substring(t, "output", out, "-sarif is not supported")
+	})
 	t.Run("a-context", func(t *testing.T) {
 		code, _, stderr := vet(t, "-c=0", "golang.org/fake/a")
 		exitcode(t, code, 1)
@@ -999,7 +999,7 @@ func (act *action) exec(ctx context.Context) (any, *actionSummary, error) {
 	}
 
 	// Now run the (pkg, analyzer) action.
-	var diagnostics []gobDiagnostic
+	var diagnostics []analysis.Diagnostic
 
 	pass := &analysis.Pass{
 		Analyzer:     analyzer,
@@ -1023,15 +1023,7 @@ func (act *action) exec(ctx context.Context) (any, *actionSummary, error) {
 				bug.Reportf("invalid SuggestedFixes: %v", err)
 				d.SuggestedFixes = nil
 			}
-			diagnostic, err := toGobDiagnostic(apkg.pkg, analyzer, d)
-			if err != nil {
-				// Don't bug.Report here: these errors all originate in
-				// posToLocation, and we can more accurately discriminate
-				// severe errors from benign ones in that function.
-				event.Error(ctx, fmt.Sprintf("internal error converting diagnostic from analyzer %q", analyzer.Name), err)
-				return
-			}
-			diagnostics = append(diagnostics, diagnostic)
+			diagnostics = append(diagnostics, d)
 		},
 		ImportObjectFact:  factset.ImportObjectFact,
 		ExportObjectFact:  factset.ExportObjectFact,
@@ -1119,9 +1111,23 @@ func (act *action) exec(ctx context.Context) (any, *actionSummary, error) {
 		panic(fmt.Sprintf("%v: Pass.ExportPackageFact(%T) called after Run", act, fact))
 	}
 
+	// Honor //lint:ignore directives.
+	var gobDiagnostics []gobDiagnostic
+	for _, d := range driverutil.SuppressDiagnostics(pass.Fset, pass.Files, analyzer, diagnostics) {
+		diagnostic, err := toGobDiagnostic(apkg.pkg, analyzer, d)
+		if err != nil {
+			// Don't bug.Report here: these errors all originate in
+			// posToLocation, and we can more accurately discriminate
+			// severe errors from benign ones in that function.
+			event.Error(ctx, fmt.Sprintf("internal error converting diagnostic from analyzer %q", analyzer.Name), err)
+			continue
+		}
+		gobDiagnostics = append(gobDiagnostics, diagnostic)
+	}
+
 	factsdata := factset.Encode()
 	return result, &actionSummary{
-		Diagnostics: diagnostics,
+		Diagnostics: gobDiagnostics,
 		Facts:       factsdata,
 		FactsHash:   file.HashOf(factsdata),
 	}, nil
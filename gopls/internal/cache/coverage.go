@@ -0,0 +1,53 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/tools/cover"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// A Coverage holds the test coverage information of the packages in a
+// directory, parsed from a coverage profile written by
+// "go test -coverprofile".
+type Coverage struct {
+	Profile protocol.DocumentURI            // the coverage profile
+	blocks  map[string][]cover.ProfileBlock // file name (package path + "/" + base name) -> blocks
+	err     error                           // error parsing the profile
+}
+
+// parseCoverage parses the coverage profile fh. A profile that does
+// not (yet) exist has no blocks.
+func parseCoverage(fh file.Handle) *Coverage {
+	c := &Coverage{Profile: fh.URI()}
+	data, err := fh.Content()
+	if err != nil {
+		return c // no profile (yet)
+	}
+	profiles, err := cover.ParseProfilesFromReader(bytes.NewReader(data))
+	if err != nil {
+		c.err = fmt.Errorf("reading coverage profile %s: %v", fh.URI().Path(), err)
+		return c
+	}
+	c.blocks = make(map[string][]cover.ProfileBlock)
+	for _, p := range profiles {
+		c.blocks[p.FileName] = p.Blocks
+	}
+	return c
+}
+
+// FileBlocks returns the coverage blocks of the file with the given
+// base name in package pkgPath, or nil if the profile does not
+// describe it.
+func (c *Coverage) FileBlocks(pkgPath PackagePath, base string) ([]cover.ProfileBlock, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.blocks[string(pkgPath)+"/"+base], nil
+}
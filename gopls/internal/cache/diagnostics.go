@@ -136,6 +136,7 @@ const (
 	TypeError              DiagnosticSource = "compiler"
 	ModTidyError           DiagnosticSource = "go mod tidy"
 	CompilerOptDetailsInfo DiagnosticSource = "optimizer details" // cmd/compile -json=0,dir
+	CoverageInfo           DiagnosticSource = "coverage"          // go test -coverprofile
 	UpgradeNotification    DiagnosticSource = "upgrade available"
 	Vulncheck              DiagnosticSource = "vulncheck imports"
 	Govulncheck            DiagnosticSource = "govulncheck"
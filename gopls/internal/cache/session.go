@@ -71,6 +71,9 @@ type Session struct {
 
 	parseCache *parseCache
 
+	tempDirMu sync.Mutex
+	tempDir   string // created by TempDir, removed by Shutdown
+
 	*overlayFS
 }
 
@@ -96,9 +99,33 @@ func (s *Session) Shutdown(ctx context.Context) {
 	}
 	s.parseCache.stop()
 	s.snapshotWG.Wait() // wait for all work on associated snapshots to finish
+	s.tempDirMu.Lock()
+	if s.tempDir != "" {
+		if err := os.RemoveAll(s.tempDir); err != nil {
+			event.Error(ctx, "removing session temporary directory", err)
+		}
+		s.tempDir = ""
+	}
+	s.tempDirMu.Unlock()
 	event.Log(ctx, "Shutdown session", KeyShutdownSession.Of(s))
 }
 
+// TempDir returns a temporary directory for files, such as test
+// coverage profiles, that the session writes. It is created on first
+// use and removed when the session shuts down.
+func (s *Session) TempDir() (string, error) {
+	s.tempDirMu.Lock()
+	defer s.tempDirMu.Unlock()
+	if s.tempDir == "" {
+		dir, err := os.MkdirTemp("", "gopls-session-")
+		if err != nil {
+			return "", err
+		}
+		s.tempDir = dir
+	}
+	return s.tempDir, nil
+}
+
 // Cache returns the cache that created this session, for debugging only.
 func (s *Session) Cache() *Cache {
 	return s.cache
@@ -404,3 +404,22 @@ func writeFiles(t *testing.T, files map[string]string) string {
 	}
 	return root
 }
+
+func TestSessionTempDir(t *testing.T) {
+	ctx := context.Background()
+	s := NewSession(ctx, New(nil))
+	dir, err := s.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir2, err := s.TempDir(); err != nil || dir2 != dir {
+		t.Fatalf("second TempDir() = %q, %v, want %q", dir2, err, dir)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "profile.out"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	s.Shutdown(ctx)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("after Shutdown, Stat(%q) = %v, want not exist", dir, err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"go/build/constraint"
 	"go/parser"
 	"go/token"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
@@ -188,6 +189,21 @@ type Snapshot struct {
 	// and tests need compiler optimization details in the diagnostics.
 	compilerOptDetails map[protocol.DocumentURI]unit
 
+	// compilerOptLogs memoizes the compiler's log of optimization
+	// decisions for each package directory. Unlike most state, it is
+	// not inherited by clones, as almost any change may affect the
+	// compiler's decisions.
+	compilerOptLogs map[protocol.DocumentURI]*memoize.Promise // *memoize.Promise[compilerOptLogResult]
+
+	// dynamicCallGraph memoizes the call graph of the workspace
+	// packages used by the call hierarchy. Like compilerOptLogs, it
+	// is not inherited by clones.
+	dynamicCallGraph *memoize.Promise // *memoize.Promise[dynamicCallGraphResult]
+
+	// coverage maps each directory whose packages need coverage
+	// information in the diagnostics to its parsed coverage profile.
+	coverage map[protocol.DocumentURI]*Coverage
+
 	// Concurrent type checking:
 	// typeCheckMu guards the ongoing type checking batch, and reference count of
 	// ongoing type checking operations.
@@ -824,6 +840,11 @@ func (s *Snapshot) fileWatchingGlobPatterns() map[protocol.RelativePattern]unit
 		patterns[protocol.RelativePattern{Pattern: glob}] = unit{}
 	}
 
+	// Watch the coverage profiles, which may be rewritten by go test.
+	for _, c := range s.coverage {
+		patterns[protocol.RelativePattern{BaseURI: c.Profile.Dir(), Pattern: path.Base(string(c.Profile))}] = unit{}
+	}
+
 	var extensions strings.Builder
 	extensions.WriteString("go,mod,sum,work")
 	for _, ext := range s.Options().TemplateExtensions {
@@ -1510,7 +1531,7 @@ func (s *Snapshot) clone(ctx, bgCtx context.Context, changed StateChange, done f
 
 	// TODO(rfindley): reorganize this function to make the derivation of
 	// needsDiagnosis clearer.
-	needsDiagnosis := len(changed.CompilerOptDetails) > 0 || len(changed.ModuleUpgrades) > 0 || len(changed.Vulns) > 0
+	needsDiagnosis := len(changed.CompilerOptDetails) > 0 || len(changed.Coverage) > 0 || len(changed.ModuleUpgrades) > 0 || len(changed.Vulns) > 0
 
 	bgCtx, cancel := context.WithCancel(bgCtx)
 	result := &Snapshot{
@@ -1559,6 +1580,36 @@ func (s *Snapshot) clone(ctx, bgCtx context.Context, changed StateChange, done f
 		}
 	}
 
+	// Likewise for the coverage profiles, which are parsed when
+	// they are set or change (and the packages re-diagnosed).
+	if len(s.coverage) > 0 || len(changed.Coverage) > 0 {
+		newCoverage := maps.Clone(s.coverage)
+		if newCoverage == nil {
+			newCoverage = make(map[protocol.DocumentURI]*Coverage)
+		}
+		for dir, profile := range changed.Coverage {
+			if profile != "" {
+				newCoverage[dir] = &Coverage{Profile: profile} // parsed below
+			} else {
+				delete(newCoverage, dir)
+			}
+		}
+		for dir, c := range newCoverage {
+			_, changedProfile := changedFiles[c.Profile]
+			if changedProfile {
+				needsDiagnosis = true
+			}
+			if changedProfile || changed.Coverage[dir] != "" {
+				if fh, ok := result.files.get(c.Profile); ok {
+					newCoverage[dir] = parseCoverage(fh)
+				}
+			}
+		}
+		if len(newCoverage) > 0 {
+			result.coverage = newCoverage
+		}
+	}
+
 	reinit := false
 	for _, mod := range changed.Modifications {
 		// Changes to vendor tree may require reinitialization,
@@ -2203,6 +2254,76 @@ func (s *Snapshot) WantCompilerOptDetails(dir protocol.DocumentURI) bool {
 	return ok
 }
 
+// CompilerOptLog returns the compiler's log of optimization decisions
+// for the packages and tests in the given directory, as computed by
+// run, which is called at most once per snapshot and directory.
+//
+// (The log is computed by the golang package, which knows how to
+// invoke the compiler; the snapshot merely memoizes it.)
+func (s *Snapshot) CompilerOptLog(ctx context.Context, dir protocol.DocumentURI, run func(context.Context, *Snapshot) (map[protocol.DocumentURI][]*Diagnostic, error)) (map[protocol.DocumentURI][]*Diagnostic, error) {
+	type compilerOptLogResult struct {
+		log map[protocol.DocumentURI][]*Diagnostic
+		err error
+	}
+
+	s.mu.Lock()
+	entry, hit := s.compilerOptLogs[dir]
+	if !hit {
+		entry = memoize.NewPromise("compilerOptLog", func(ctx context.Context, arg any) any {
+			log, err := run(ctx, arg.(*Snapshot))
+			return compilerOptLogResult{log, err}
+		})
+		if s.compilerOptLogs == nil {
+			s.compilerOptLogs = make(map[protocol.DocumentURI]*memoize.Promise)
+		}
+		s.compilerOptLogs[dir] = entry
+	}
+	s.mu.Unlock()
+
+	v, err := s.awaitPromise(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	res := v.(compilerOptLogResult)
+	return res.log, res.err
+}
+
+// DynamicCallGraph returns the call graph of the workspace packages,
+// including their dynamic calls, as computed by build, which is called
+// at most once per snapshot.
+//
+// (The graph is computed by the golang package, which defines its
+// representation; the snapshot merely memoizes it.)
+func (s *Snapshot) DynamicCallGraph(ctx context.Context, build func(context.Context, *Snapshot) (any, error)) (any, error) {
+	type dynamicCallGraphResult struct {
+		graph any
+		err   error
+	}
+
+	s.mu.Lock()
+	if s.dynamicCallGraph == nil {
+		s.dynamicCallGraph = memoize.NewPromise("dynamicCallGraph", func(ctx context.Context, arg any) any {
+			graph, err := build(ctx, arg.(*Snapshot))
+			return dynamicCallGraphResult{graph, err}
+		})
+	}
+	entry := s.dynamicCallGraph
+	s.mu.Unlock()
+
+	v, err := s.awaitPromise(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	res := v.(dynamicCallGraphResult)
+	return res.graph, res.err
+}
+
+// Coverage returns the coverage information reported for packages
+// and tests in the given directory, or nil if none.
+func (s *Snapshot) Coverage(dir protocol.DocumentURI) *Coverage {
+	return s.coverage[dir]
+}
+
 // A CodeLensSourceFunc is a function that reports CodeLenses (range-associated
 // commands) for a given file.
 type CodeLensSourceFunc func(context.Context, *Snapshot, file.Handle) ([]protocol.CodeLens, error)
@@ -753,7 +753,8 @@ type StateChange struct {
 	Files              map[protocol.DocumentURI]file.Handle
 	ModuleUpgrades     map[protocol.DocumentURI]map[string]string
 	Vulns              map[protocol.DocumentURI]*vulncheck.Result
-	CompilerOptDetails map[protocol.DocumentURI]bool // package directory -> whether or not we want details
+	CompilerOptDetails map[protocol.DocumentURI]bool                 // package directory -> whether or not we want details
+	Coverage           map[protocol.DocumentURI]protocol.DocumentURI // package directory -> coverage profile (also in Files), or "" for none
 }
 
 // InvalidateView processes the provided state change, invalidating any derived
@@ -292,6 +292,7 @@ func (app *Application) featureCommands() []tool.Application {
 		&highlight{app: app},
 		&implementation{app: app},
 		&imports{app: app},
+		&index{app: app},
 		newRemote(app),
 		&links{app: app},
 		&prepareRename{app: app},
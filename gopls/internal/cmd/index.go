@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/tool"
+)
+
+// index implements the index verb for gopls.
+type index struct {
+	Output  string `flag:"o,output" help:"file to which to write the dump; if unset, writes to stdout"`
+	Version string `flag:"version" help:"version of the indexed module, recorded in its monikers"`
+
+	app *Application
+}
+
+func (i *index) Name() string      { return "index" }
+func (i *index) Parent() string    { return i.app.Name() }
+func (i *index) Usage() string     { return "[index-flags]" }
+func (i *index) ShortHelp() string { return "write an LSIF dump of the workspace" }
+func (i *index) DetailedHelp(f *flag.FlagSet) {
+	fmt.Fprint(f.Output(), `
+The index command writes an LSIF (Language Server Index Format) dump of
+the packages of the workspace in the current directory, including their
+tests. The dump records the definitions, references, hover text, and
+monikers of their symbols, for use by code navigation tools.
+
+Example: write a dump of version v1.2.3 of the module in the current directory:
+
+	$ gopls index -version=v1.2.3 -o=dump.lsif
+
+index-flags:
+`)
+	printFlagDefaults(f)
+}
+
+// Run writes the dump of the workspace.
+func (i *index) Run(ctx context.Context, args ...string) error {
+	if len(args) != 0 {
+		return tool.CommandLineErrorf("index expects no arguments")
+	}
+	cli, sess, err := i.app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.terminate(ctx)
+	if sess == nil {
+		return fmt.Errorf("index is not supported with -remote")
+	}
+	views := sess.Views()
+	if len(views) == 0 {
+		return fmt.Errorf("no workspace in the current directory")
+	}
+	snapshot, release, err := views[0].Snapshot()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	out := os.Stdout
+	if i.Output != "" {
+		f, err := os.Create(i.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // ignore error in case of failure; see Close below
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	if err := golang.WriteLSIF(ctx, snapshot, w, i.Version); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		return out.Close() // report errors of a short write
+	}
+	return nil
+}
@@ -582,6 +582,72 @@ func _() {
 	}
 }
 
+// TestIndex tests the 'index' subcommand (index.go).
+func TestIndex(t *testing.T) {
+	t.Parallel()
+
+	tree := writeTree(t, `
+-- go.mod --
+module example.com
+go 1.18
+
+-- a/a.go --
+package a
+
+import "fmt"
+
+func F() { fmt.Println() }
+
+-- a/a_test.go --
+package a
+
+func g() { F() }
+`)
+	// arguments
+	{
+		res := gopls(t, tree, "index", "a")
+		res.checkExit(false)
+		res.checkStderr("expects no arguments")
+	}
+	// success
+	{
+		res := gopls(t, tree, "index", "-version=v1.2.3")
+		res.checkExit(true)
+		res.checkStdout(`"label":"metaData"`)
+		res.checkStdout(`"uri":"file://.*/a/a_test.go"`)
+		res.checkStdout(regexp.QuoteMeta(`"identifier":"example.com@v1.2.3 example.com/a#F"`))
+		res.checkStdout(regexp.QuoteMeta(`"identifier":"std fmt#Println"`))
+		res.checkStdout(regexp.QuoteMeta(`"label":"packageInformation","manager":"gomod","name":"example.com","type":"vertex","version":"v1.2.3"`))
+
+		// Check that the elements are well-formed and that
+		// edges refer only to previous vertices.
+		vertices := make(map[int]bool)
+		for line := range strings.Lines(res.stdout) {
+			var elem struct {
+				ID    int
+				Type  string
+				Label string
+				OutV  int
+				InV   int
+				InVs  []int
+			}
+			if err := json.Unmarshal([]byte(line), &elem); err != nil {
+				t.Fatalf("invalid element %q: %v", line, err)
+			}
+			switch elem.Type {
+			case "vertex":
+				vertices[elem.ID] = true
+			case "edge":
+				for _, v := range append(elem.InVs, elem.OutV, elem.InV) {
+					if v != 0 && !vertices[v] {
+						t.Errorf("%s edge %d refers to unknown vertex %d", elem.Label, elem.ID, v)
+					}
+				}
+			}
+		}
+	}
+}
+
 // TestLinks tests the 'links' subcommand (links.go).
 func TestLinks(t *testing.T) {
 	t.Parallel()
@@ -73,7 +73,19 @@ func {{.TestFuncName}}(t *{{.TestingPackageName}}.T) {
 		{{- end}}
 		{{- end}}
 	}{
+		{{- range .Cases}}
+		{
+			name: {{printf "%q" .Name}},
+			// {{.Comment}}
+			{{- range $.Func.Args}}
+			{{- if and .Name .Zero}}
+			{{.Name}}: {{.Zero}},
+			{{- end}}
+			{{- end}}
+		},
+		{{- else}}
 		// TODO: Add test cases.
+		{{- end}}
 	}
 
 	{{- /* Loop over all the test cases. */}}
@@ -162,8 +174,11 @@ func {{.TestFuncName}}(t *{{.TestingPackageName}}.T) {
 // Value is the expression this input parameter should accept.
 //
 // Exactly one of Name or Value must be set.
+//
+// Zero is the zero value of a named input parameter, with which the
+// seeded test cases initialize it, or "" if it has no literal form.
 type field struct {
-	Name, Type, Value string
+	Name, Type, Value, Zero string
 }
 
 type function struct {
@@ -199,6 +214,9 @@ type testInfo struct {
 	// being tested.
 	// This field is nil for functions and non-nil for methods.
 	Receiver *receiver
+	// Cases holds the test cases with which to seed the table, one per
+	// branch of the function; if empty, the table is left to fill in.
+	Cases []testCase
 }
 
 var testTmpl = template.Must(template.New("test").Funcs(template.FuncMap{
@@ -527,6 +545,9 @@ func AddTestForFunc(ctx context.Context, snapshot *cache.Snapshot, loc protocol.
 			default:
 				f.Type = types.TypeString(typ, qual)
 				f.Name = name
+				if zero, ok := typesinternal.ZeroString(typ, qual); ok {
+					f.Zero = zero
+				}
 			}
 			fn.Args = append(fn.Args, f)
 		}
@@ -534,6 +555,10 @@ func AddTestForFunc(ctx context.Context, snapshot *cache.Snapshot, loc protocol.
 
 	populateArgs(&data.Func, sig)
 
+	if snapshot.Options().AddTestBranchCases {
+		data.Cases = branchTestCases(pkg.FileSet(), pkg.TypesInfo(), decl)
+	}
+
 	for i := range sig.Results().Len() {
 		typ := sig.Results().At(i).Type()
 		var name string
@@ -0,0 +1,218 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the seeding of the table of a test generated by
+// "Add test for FUNC" with a case for each path through the function.
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+)
+
+// A testCase is an entry of the table of a generated test, for one
+// path through the function under test.
+type testCase struct {
+	Name    string // name of the test case
+	Comment string // description of the path, as a TODO comment
+}
+
+// branchTestCases returns a test case for each distinct return path,
+// and for each switch case, of the function declaration decl, in
+// source order. The cases are derived from the control-flow graph of
+// the function: each describes the conditions of the branches along a
+// shortest path from the entry of the function to its target.
+func branchTestCases(fset *token.FileSet, info *types.Info, decl *ast.FuncDecl) []testCase {
+	if decl.Body == nil {
+		return nil
+	}
+	mayReturn := func(call *ast.CallExpr) bool {
+		id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		return !(ok && info.Uses[id] == types.Universe.Lookup("panic"))
+	}
+	g := cfg.New(decl.Body, mayReturn)
+
+	// Record the switch statement of each case expression,
+	// and the type switch statement of each case clause.
+	var (
+		caseSwitch       = make(map[ast.Expr]*ast.SwitchStmt)
+		clauseTypeSwitch = make(map[*ast.CaseClause]*ast.TypeSwitchStmt)
+	)
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // not part of the graph
+		case *ast.SwitchStmt:
+			for _, clause := range n.Body.List {
+				for _, e := range clause.(*ast.CaseClause).List {
+					caseSwitch[e] = n
+				}
+			}
+		case *ast.TypeSwitchStmt:
+			for _, clause := range n.Body.List {
+				clauseTypeSwitch[clause.(*ast.CaseClause)] = n
+			}
+		}
+		return true
+	})
+
+	// Find a shortest path from the entry to each reachable block.
+	pred := map[*cfg.Block]*cfg.Block{g.Blocks[0]: nil}
+	queue := []*cfg.Block{g.Blocks[0]}
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		for _, succ := range b.Succs {
+			if _, ok := pred[succ]; !ok {
+				pred[succ] = b
+				queue = append(queue, succ)
+			}
+		}
+	}
+
+	// describe returns a description of the condition under which
+	// control flows along the edge from p to its successor s, or ""
+	// if there is no such condition, or it cannot be expressed.
+	describe := func(p, s *cfg.Block) string {
+		if len(p.Succs) != 2 || len(p.Nodes) == 0 && s.Kind != cfg.KindSwitchCaseBody {
+			return ""
+		}
+		holds := s == p.Succs[0]
+		if len(p.Nodes) > 0 {
+			if cond, ok := p.Nodes[len(p.Nodes)-1].(ast.Expr); ok {
+				switch s.Kind {
+				case cfg.KindIfThen, cfg.KindIfElse, cfg.KindIfDone, cfg.KindForBody, cfg.KindForDone:
+				case cfg.KindSwitchCaseBody, cfg.KindSwitchNextCase:
+					if sw := caseSwitch[cond]; sw != nil && sw.Tag != nil {
+						op := "=="
+						if !holds {
+							op = "!="
+						}
+						return fmt.Sprintf("%s %s %s", types.ExprString(sw.Tag), op, types.ExprString(cond))
+					}
+				default:
+					return ""
+				}
+				if holds {
+					return types.ExprString(cond)
+				}
+				return negateCond(cond)
+			}
+		}
+		// The true edge of the test of a type switch case.
+		if cc, ok := s.Stmt.(*ast.CaseClause); ok && holds && s.Kind == cfg.KindSwitchCaseBody {
+			if ts := clauseTypeSwitch[cc]; ts != nil {
+				var subject ast.Expr
+				switch assign := ts.Assign.(type) {
+				case *ast.AssignStmt:
+					subject = assign.Rhs[0]
+				case *ast.ExprStmt:
+					subject = assign.X
+				}
+				if assert, ok := subject.(*ast.TypeAssertExpr); ok {
+					var types_ []string
+					for _, t := range cc.List {
+						types_ = append(types_, types.ExprString(t))
+					}
+					return fmt.Sprintf("%s is %s", types.ExprString(assert.X), strings.Join(types_, " or "))
+				}
+			}
+		}
+		return ""
+	}
+
+	// Collect the targets: returns (including the implicit return at
+	// the end of the function, if reachable), and switch cases.
+	type target struct {
+		block *cfg.Block
+		pos   token.Pos
+		name  string
+	}
+	var targets []target
+	for _, b := range g.Blocks {
+		if _, ok := pred[b]; !ok {
+			continue // unreachable
+		}
+		if ret := b.Return(); ret != nil {
+			name := "end of function" // implicit return
+			if ret.Return != decl.Body.Rbrace {
+				var results []string
+				for _, res := range ret.Results {
+					results = append(results, types.ExprString(res))
+				}
+				name = strings.TrimSpace("return " + strings.Join(results, ", "))
+			}
+			targets = append(targets, target{b, ret.Pos(), name})
+		} else if cc, ok := b.Stmt.(*ast.CaseClause); ok && b.Kind == cfg.KindSwitchCaseBody {
+			name := "default"
+			if cc.List != nil {
+				var exprs []string
+				for _, e := range cc.List {
+					exprs = append(exprs, types.ExprString(e))
+				}
+				name = "case " + strings.Join(exprs, ", ")
+			}
+			targets = append(targets, target{b, cc.Pos(), name})
+		}
+	}
+	slices.SortStableFunc(targets, func(x, y target) int { return int(x.pos - y.pos) })
+
+	var cases []testCase
+	names := make(map[string]bool)
+	for _, t := range targets {
+		var conds []string
+		for s := t.block; pred[s] != nil; s = pred[s] {
+			if cond := describe(pred[s], s); cond != "" {
+				conds = append(conds, cond)
+			}
+		}
+		slices.Reverse(conds)
+
+		line := safetoken.StartPosition(fset, t.pos).Line
+		name := t.name
+		if names[name] {
+			name = fmt.Sprintf("%s (line %d)", name, line)
+		}
+		names[name] = true
+		comment := fmt.Sprintf("TODO: choose inputs to reach line %d.", line)
+		if len(conds) > 0 {
+			comment = fmt.Sprintf("TODO: choose inputs such that %s, to reach line %d.", strings.Join(conds, " && "), line)
+		}
+		cases = append(cases, testCase{Name: name, Comment: comment})
+	}
+	return cases
+}
+
+// negateCond returns the negation of the condition cond, as a string.
+func negateCond(cond ast.Expr) string {
+	switch e := ast.Unparen(cond).(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return types.ExprString(e.X)
+		}
+	case *ast.BinaryExpr:
+		negations := map[token.Token]token.Token{
+			token.EQL: token.NEQ,
+			token.NEQ: token.EQL,
+			token.LSS: token.GEQ,
+			token.GEQ: token.LSS,
+			token.GTR: token.LEQ,
+			token.LEQ: token.GTR,
+		}
+		if op, ok := negations[e.Op]; ok {
+			return fmt.Sprintf("%s %s %s", types.ExprString(e.X), op, types.ExprString(e.Y))
+		}
+	case *ast.Ident, *ast.SelectorExpr, *ast.CallExpr, *ast.IndexExpr:
+		return "!" + types.ExprString(e)
+	}
+	return "!(" + types.ExprString(cond) + ")"
+}
@@ -18,6 +18,7 @@ import (
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/util/moremaps"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
 	"golang.org/x/tools/internal/astutil"
 	"golang.org/x/tools/internal/event"
 	"golang.org/x/tools/internal/typesinternal"
@@ -91,6 +92,34 @@ func IncomingCalls(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle
 	for _, callItem := range moremaps.SortedFunc(incomingCalls, protocol.CompareLocation) {
 		incomingCallItems = append(incomingCallItems, *callItem)
 	}
+
+	// Optionally add the dynamic calls (through interfaces and
+	// function values) found by VTA.
+	if snapshot.Options().DynamicCallHierarchy {
+		pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+		if err != nil {
+			return nil, err
+		}
+		start, end, err := pgf.RangePos(rng)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := callHierarchyFuncAtRange(pkg.TypesInfo(), pgf, astutil.RangeOf(start, end))
+		if err != nil || isBuiltin(obj) {
+			return incomingCallItems, nil
+		}
+		dynamic, err := dynamicIncomingCalls(ctx, snapshot, safetoken.StartPosition(pkg.FileSet(), obj.Pos()))
+		if err != nil {
+			return nil, err
+		}
+		for _, call := range dynamic {
+			// Calls from a function with syntactic references,
+			// such as calls of an interface method, are not repeated.
+			if _, ok := incomingCalls[call.From.URI.Location(call.From.Range)]; !ok {
+				incomingCallItems = append(incomingCallItems, call)
+			}
+		}
+	}
 	return incomingCallItems, nil
 }
 
@@ -271,6 +300,20 @@ func OutgoingCalls(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle
 	for _, callItem := range moremaps.SortedFunc(outgoingCalls, protocol.CompareLocation) {
 		outgoingCallItems = append(outgoingCallItems, *callItem)
 	}
+
+	// Optionally add the dynamic calls (through interfaces and
+	// function values) found by VTA.
+	if snapshot.Options().DynamicCallHierarchy {
+		dynamic, err := dynamicOutgoingCalls(ctx, snapshot, safetoken.Position(declPGF.Tok, declPos))
+		if err != nil {
+			return nil, err
+		}
+		for _, call := range dynamic {
+			if _, ok := outgoingCalls[call.To.URI.Location(call.To.Range)]; !ok {
+				outgoingCallItems = append(outgoingCallItems, call)
+			}
+		}
+	}
 	return outgoingCallItems, nil
 }
 
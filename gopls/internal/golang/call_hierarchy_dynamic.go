@@ -0,0 +1,452 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the dynamic call edges of the call hierarchy,
+// computed from a call graph of the workspace by variable type
+// analysis (VTA), when the dynamicCallHierarchy setting is enabled.
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	goastutil "golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/internal/util/tokeninternal"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/typesinternal"
+	"golang.org/x/tools/internal/versions"
+)
+
+// A dynamicCallGraph is a call graph of the workspace packages,
+// computed by VTA, whose edges include the dynamic calls of
+// interface methods and function values.
+//
+// The workspace packages, and their dependencies, are type-checked
+// anew, in a single realm of types, as required by go/ssa: the types
+// of the packages returned by [cache.Snapshot.TypeCheck] may belong to
+// different realms. Only the function bodies of workspace packages
+// are checked.
+type dynamicCallGraph struct {
+	fset  *token.FileSet
+	graph *callgraph.Graph
+	files map[*token.File]*parsego.File // syntax of the workspace packages
+}
+
+// snapshotDynamicCallGraph returns the dynamic call graph of the
+// workspace packages, which is built at most once per snapshot.
+func snapshotDynamicCallGraph(ctx context.Context, snapshot *cache.Snapshot) (*dynamicCallGraph, error) {
+	g, err := snapshot.DynamicCallGraph(ctx, func(ctx context.Context, snapshot *cache.Snapshot) (any, error) {
+		return buildDynamicCallGraph(ctx, snapshot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g.(*dynamicCallGraph), nil
+}
+
+// buildDynamicCallGraph builds the SSA form of the workspace packages,
+// excluding test variants, and computes their call graph by VTA.
+// Workspace packages with type errors are built from type information
+// alone, so the calls within them are omitted; such packages are
+// logged.
+//
+// It is expensive: it must type-check and build all the workspace
+// packages, and the declarations of all their dependencies.
+func buildDynamicCallGraph(ctx context.Context, snapshot *cache.Snapshot) (*dynamicCallGraph, error) {
+	mps, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspace := make(map[PackageID]bool)
+	for _, mp := range mps {
+		if mp.ForTest == "" && !metadata.IsCommandLineArguments(mp.ID) {
+			workspace[mp.ID] = true
+		}
+	}
+	meta := snapshot.MetadataGraph()
+
+	var (
+		tokFiles []*token.File
+		files    = make(map[*token.File]*parsego.File)
+		checked  = make(map[PackageID]*types.Package)
+		syntax   = make(map[*types.Package][]*ast.File) // well-typed workspace packages
+		infos    = make(map[*types.Package]*types.Info)
+		skipped  []PackagePath
+	)
+	var check func(id PackageID) (*types.Package, error)
+	check = func(id PackageID) (*types.Package, error) {
+		if pkg, ok := checked[id]; ok {
+			return pkg, nil
+		}
+		mp := meta.Packages[id]
+		if mp == nil {
+			return nil, fmt.Errorf("no metadata for %s", id)
+		}
+		if mp.PkgPath == "unsafe" {
+			return types.Unsafe, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var pgfs []*parsego.File
+		for _, uri := range mp.CompiledGoFiles {
+			fh, err := snapshot.ReadFile(ctx, uri)
+			if err != nil {
+				return nil, err
+			}
+			pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+			if err != nil {
+				return nil, err
+			}
+			pgfs = append(pgfs, pgf)
+		}
+
+		var (
+			astFiles []*ast.File
+			toks     []*token.File
+		)
+		for _, pgf := range pgfs {
+			astFiles = append(astFiles, pgf.File)
+			toks = append(toks, pgf.Tok)
+		}
+		slices.SortFunc(toks, func(x, y *token.File) int { return cmp.Compare(x.Base(), y.Base()) })
+
+		var info *types.Info
+		if workspace[id] {
+			info = &types.Info{
+				Types:        make(map[ast.Expr]types.TypeAndValue),
+				Defs:         make(map[*ast.Ident]types.Object),
+				Uses:         make(map[*ast.Ident]types.Object),
+				Implicits:    make(map[ast.Node]types.Object),
+				Instances:    make(map[*ast.Ident]types.Instance),
+				Scopes:       make(map[ast.Node]*types.Scope),
+				Selections:   make(map[*ast.SelectorExpr]*types.Selection),
+				FileVersions: make(map[*ast.File]string),
+			}
+		}
+		hasErrors := false
+		cfg := &types.Config{
+			Importer: ImporterFunc(func(path string) (*types.Package, error) {
+				depID := mp.DepsByImpPath[metadata.ImportPath(path)]
+				if depID == "" {
+					return nil, fmt.Errorf("missing package %q", path)
+				}
+				return check(depID)
+			}),
+			IgnoreFuncBodies: !workspace[id],
+			Error:            func(error) { hasErrors = true },
+			Sizes:            mp.TypesSizes,
+		}
+		if mp.Module != nil && versions.IsValid("go"+mp.Module.GoVersion) {
+			cfg.GoVersion = "go" + mp.Module.GoVersion
+		}
+		typesinternal.SetUsesCgo(cfg)
+		pkg, _ := cfg.Check(string(mp.PkgPath), tokeninternal.FileSetFor(toks...), astFiles, info)
+		checked[id] = pkg
+
+		// Only well-typed packages can be built from syntax;
+		// the others are built from type information alone.
+		if workspace[id] {
+			if hasErrors {
+				skipped = append(skipped, mp.PkgPath)
+			} else {
+				syntax[pkg] = astFiles
+				infos[pkg] = info
+				for _, pgf := range pgfs {
+					files[pgf.Tok] = pgf
+				}
+			}
+		}
+		tokFiles = append(tokFiles, toks...)
+		return pkg, nil
+	}
+	for id := range workspace {
+		if _, err := check(id); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(tokFiles, func(x, y *token.File) int { return cmp.Compare(x.Base(), y.Base()) })
+	tokFiles = slices.Compact(tokFiles)
+	fset := tokeninternal.FileSetFor(tokFiles...)
+
+	prog := ssa.NewProgram(fset, ssa.InstantiateGenerics)
+	var create func(pkg *types.Package)
+	create = func(pkg *types.Package) {
+		if prog.Package(pkg) != nil {
+			return
+		}
+		prog.CreatePackage(pkg, syntax[pkg], infos[pkg], true)
+		for _, imp := range pkg.Imports() {
+			create(imp)
+		}
+	}
+	for _, pkg := range checked {
+		create(pkg)
+	}
+	prog.Build()
+
+	if len(skipped) > 0 {
+		slices.Sort(skipped)
+		event.Log(ctx, fmt.Sprintf("dynamic call graph omits the calls within packages with type errors: %v", skipped))
+	}
+
+	graph := vta.CallGraph(ssautil.AllFunctions(prog), cha.CallGraph(prog))
+	return &dynamicCallGraph{fset: fset, graph: graph, files: files}, nil
+}
+
+// nodesOf returns the nodes of the graph for the function or method
+// (or any of its instantiations) declared at the specified position.
+func (g *dynamicCallGraph) nodesOf(declPosn token.Position) []*callgraph.Node {
+	var nodes []*callgraph.Node
+	for fn, node := range g.graph.Nodes {
+		if fn == nil || fn.Object() == nil || isWrapper(fn) {
+			continue
+		}
+		posn := safetoken.StartPosition(g.fset, fn.Object().Pos())
+		if posn.Filename == declPosn.Filename && posn.Offset == declPosn.Offset {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// A dynamicCall is a dynamic call site of the call graph.
+type dynamicCall struct {
+	pkgPath PackagePath       // package of the calling function
+	loc     protocol.Location // location of the called function's name
+	callee  *ssa.Function     // a possible callee
+}
+
+// incoming returns the dynamic call sites that may call the function
+// declared at declPosn, directly, or through a wrapper such as a bound
+// method closure.
+func (g *dynamicCallGraph) incoming(declPosn token.Position) []dynamicCall {
+	var (
+		calls []dynamicCall
+		seen  = make(map[*callgraph.Node]bool)
+		queue = g.nodesOf(declPosn)
+	)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if seen[node] {
+			continue
+		}
+		seen[node] = true
+		for _, e := range node.In {
+			if isWrapper(e.Caller.Func) {
+				queue = append(queue, e.Caller)
+				continue
+			}
+			if e.Site == nil || e.Site.Common().StaticCallee() != nil {
+				continue // static calls are found by references
+			}
+			if call, ok := g.callSite(e, node.Func); ok {
+				calls = append(calls, call)
+			}
+		}
+	}
+	return calls
+}
+
+// outgoing returns the dynamic call sites within the function declared
+// at declPosn, or its function literals, and their possible callees,
+// looking through wrappers.
+func (g *dynamicCallGraph) outgoing(declPosn token.Position) []dynamicCall {
+	var (
+		calls []dynamicCall
+		seen  = make(map[*callgraph.Node]bool)
+	)
+	// callees calls f for each non-wrapper function reachable from
+	// node through wrappers.
+	var callees func(node *callgraph.Node, f func(*ssa.Function))
+	callees = func(node *callgraph.Node, f func(*ssa.Function)) {
+		if !isWrapper(node.Func) {
+			f(node.Func)
+			return
+		}
+		if seen[node] {
+			return
+		}
+		seen[node] = true
+		for _, e := range node.Out {
+			callees(e.Callee, f)
+		}
+	}
+	var visit func(node *callgraph.Node)
+	visit = func(node *callgraph.Node) {
+		for _, e := range node.Out {
+			if e.Site == nil || e.Site.Common().StaticCallee() != nil {
+				continue // static calls are found syntactically
+			}
+			callees(e.Callee, func(callee *ssa.Function) {
+				if call, ok := g.callSite(e, callee); ok {
+					calls = append(calls, call)
+				}
+			})
+		}
+		for _, anon := range node.Func.AnonFuncs {
+			if anon := g.graph.Nodes[anon]; anon != nil {
+				visit(anon)
+			}
+		}
+	}
+	for _, node := range g.nodesOf(declPosn) {
+		visit(node)
+	}
+	return calls
+}
+
+// callSite returns the dynamic call of the edge e to callee.
+// It fails if the syntax of the call cannot be found.
+func (g *dynamicCallGraph) callSite(e *callgraph.Edge, callee *ssa.Function) (dynamicCall, bool) {
+	pos := e.Site.Pos()
+	pgf, ok := g.files[g.fset.File(pos)]
+	if !ok {
+		return dynamicCall{}, false
+	}
+	path, _ := goastutil.PathEnclosingInterval(pgf.File, pos, pos)
+	for _, n := range path {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || call.Lparen != pos {
+			continue
+		}
+		var fun ast.Node = call.Fun
+		switch f := ast.Unparen(call.Fun).(type) {
+		case *ast.Ident:
+			fun = f
+		case *ast.SelectorExpr:
+			fun = f.Sel
+		}
+		loc, err := pgf.NodeLocation(fun)
+		if err != nil {
+			return dynamicCall{}, false
+		}
+		var pkgPath PackagePath
+		if caller := e.Caller.Func; caller.Pkg != nil {
+			pkgPath = PackagePath(caller.Pkg.Pkg.Path())
+		}
+		return dynamicCall{pkgPath: pkgPath, loc: loc, callee: callee}, true
+	}
+	return dynamicCall{}, false
+}
+
+// isWrapper reports whether fn is a synthetic wrapper function, such
+// as a bound method closure, that calls the function it wraps.
+func isWrapper(fn *ssa.Function) bool {
+	return fn != nil && (strings.HasPrefix(fn.Synthetic, "wrapper for ") ||
+		strings.HasPrefix(fn.Synthetic, "thunk for ") ||
+		strings.HasPrefix(fn.Synthetic, "bound method wrapper for "))
+}
+
+// dynamicIncomingCalls returns the call hierarchy items of the
+// functions that may dynamically call the function declared at the
+// specified position, according to VTA.
+func dynamicIncomingCalls(ctx context.Context, snapshot *cache.Snapshot, declPosn token.Position) ([]protocol.CallHierarchyIncomingCall, error) {
+	g, err := snapshotDynamicCallGraph(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	incomingCalls := make(map[protocol.Location]*protocol.CallHierarchyIncomingCall)
+	for _, call := range g.incoming(declPosn) {
+		callItem, err := enclosingNodeCallItem(ctx, snapshot, call.pkgPath, call.loc)
+		if err != nil {
+			continue
+		}
+		callItem.Detail += " • dynamic"
+		loc := callItem.URI.Location(callItem.Range)
+		in, ok := incomingCalls[loc]
+		if !ok {
+			in = &protocol.CallHierarchyIncomingCall{From: callItem}
+			incomingCalls[loc] = in
+		}
+		if !slices.Contains(in.FromRanges, call.loc.Range) {
+			in.FromRanges = append(in.FromRanges, call.loc.Range)
+		}
+	}
+	var items []protocol.CallHierarchyIncomingCall
+	for _, in := range incomingCalls {
+		slices.SortFunc(in.FromRanges, protocol.CompareRange)
+		items = append(items, *in)
+	}
+	slices.SortFunc(items, func(x, y protocol.CallHierarchyIncomingCall) int {
+		return protocol.CompareLocation(x.From.URI.Location(x.From.Range), y.From.URI.Location(y.From.Range))
+	})
+	return items, nil
+}
+
+// dynamicOutgoingCalls returns the call hierarchy items of the
+// functions that may be dynamically called by the function declared
+// at the specified position, according to VTA. Anonymous callees are
+// reported as their enclosing named function.
+func dynamicOutgoingCalls(ctx context.Context, snapshot *cache.Snapshot, declPosn token.Position) ([]protocol.CallHierarchyOutgoingCall, error) {
+	g, err := snapshotDynamicCallGraph(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	outgoingCalls := make(map[protocol.Location]*protocol.CallHierarchyOutgoingCall)
+	for _, call := range g.outgoing(declPosn) {
+		fn := call.callee
+		for fn.Parent() != nil {
+			fn = fn.Parent()
+		}
+		if fn.Object() == nil {
+			continue // e.g. package initializer
+		}
+		obj := fn.Object()
+		if origin := fn.Origin(); origin != nil {
+			obj = origin.Object()
+		}
+		loc, err := ObjectLocation(ctx, g.fset, snapshot, obj)
+		if err != nil {
+			return nil, err
+		}
+		out, ok := outgoingCalls[loc]
+		if !ok {
+			out = &protocol.CallHierarchyOutgoingCall{
+				To: protocol.CallHierarchyItem{
+					Name:           obj.Name(),
+					Kind:           protocol.Function,
+					Tags:           []protocol.SymbolTag{},
+					Detail:         callHierarchyItemDetail(obj, loc) + " • dynamic",
+					URI:            loc.URI,
+					Range:          loc.Range,
+					SelectionRange: loc.Range,
+				},
+			}
+			outgoingCalls[loc] = out
+		}
+		if !slices.Contains(out.FromRanges, call.loc.Range) {
+			out.FromRanges = append(out.FromRanges, call.loc.Range)
+		}
+	}
+	var items []protocol.CallHierarchyOutgoingCall
+	for _, out := range outgoingCalls {
+		slices.SortFunc(out.FromRanges, protocol.CompareRange)
+		items = append(items, *out)
+	}
+	slices.SortFunc(items, func(x, y protocol.CallHierarchyOutgoingCall) int {
+		return protocol.CompareLocation(x.To.URI.Location(x.To.Range), y.To.URI.Location(y.To.Range))
+	})
+	return items, nil
+}
@@ -14,6 +14,7 @@ import (
 	"go/token"
 	"go/types"
 	"regexp"
+	"slices"
 
 	goastutil "golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/gopls/internal/cache"
@@ -223,65 +224,16 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 
 	// Step 2: build a wrapper function calling the new declaration.
 
-	var (
-		params   = astutil.CloneNode(info.decl.Type.Params) // parameters of wrapper func: "_" names must be modified
-		args     = make([]ast.Expr, len(newParams))         // arguments to the delegated call
-		variadic = false                                    // whether the signature is variadic
-	)
-	{
-		// Record names used by non-blank parameters, just in case the user had a
-		// parameter named 'blank0', which would conflict with the synthetic names
-		// we construct below.
-		// TODO(rfindley): add an integration test for this behavior.
-		nonBlankNames := make(map[string]bool) // for detecting conflicts with renamed blanks
-		for _, fld := range params.List {
-			for _, n := range fld.Names {
-				if n.Name != "_" {
-					nonBlankNames[n.Name] = true
-				}
-			}
-			if len(fld.Names) == 0 {
-				// All parameters must have a non-blank name. For convenience, give
-				// this field a blank name.
-				fld.Names = append(fld.Names, ast.NewIdent("_")) // will be named below
-			}
-		}
-		// oldParams maps parameters to their argument in the delegated call.
-		// In other words, it is the inverse of newParams, but it is represented as
-		// a map rather than a slice, as not every old param need exist in
-		// newParams.
-		oldParams := make(map[int]int)
-		for new, old := range newParams {
-			oldParams[old] = new
-		}
-		blanks := 0
-		paramIndex := 0 // global param index.
-		for id, field := range astutil.FlatFields(params) {
-			argIndex, ok := oldParams[paramIndex]
-			paramIndex++
-			if !ok {
-				continue // parameter is removed
-			}
-			if id.Name == "_" { // from above: every field has names
-				// Create names for blank (_) parameters so the delegating wrapper
-				// can refer to them.
-				for {
-					// These names will not be seen by the user, so give them an
-					// arbitrary name.
-					newName := fmt.Sprintf("blank%d", blanks)
-					blanks++
-					if !nonBlankNames[newName] {
-						id.Name = newName
-						break
-					}
-				}
-			}
-			args[argIndex] = ast.NewIdent(id.Name)
-			// Record whether the call has an ellipsis.
-			// (Only the last loop iteration matters.)
-			_, variadic = field.Type.(*ast.Ellipsis)
-		}
+	params, names, variadic := delegatingParams(info.decl, func(i int) bool {
+		return slices.Contains(newParams, i)
+	})
+	args := make([]ast.Expr, len(newParams)) // arguments to the delegated call
+	for i, old := range newParams {
+		args[i] = ast.NewIdent(names[old])
 	}
+	// The delegated call has an ellipsis only if it retains the
+	// variadic parameter, which is necessarily last.
+	variadic = variadic && slices.Contains(newParams, len(names)-1)
 
 	// Step 3: Rewrite all referring calls, by swapping in the wrapper and
 	// inlining all.
@@ -322,7 +274,61 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		newContent[pgf.URI] = src
 	}
 
-	// Translate the resulting state into document changes.
+	return documentChanges(ctx, snapshot, newContent)
+}
+
+// delegatingParams returns a copy of the parameters of decl in which
+// every parameter i for which used(i) holds has a non-blank name, so
+// that a wrapper with these parameters may delegate to a new
+// declaration, along with the flattened list of parameter names, and
+// whether the last parameter is variadic.
+func delegatingParams(decl *ast.FuncDecl, used func(i int) bool) (params *ast.FieldList, names []string, variadic bool) {
+	params = astutil.CloneNode(decl.Type.Params) // "_" names must be modified
+
+	// Record names used by non-blank parameters, just in case the user had a
+	// parameter named 'blank0', which would conflict with the synthetic names
+	// we construct below.
+	// TODO(rfindley): add an integration test for this behavior.
+	nonBlankNames := make(map[string]bool) // for detecting conflicts with renamed blanks
+	for _, fld := range params.List {
+		for _, n := range fld.Names {
+			if n.Name != "_" {
+				nonBlankNames[n.Name] = true
+			}
+		}
+		if len(fld.Names) == 0 {
+			// All parameters must have a non-blank name. For convenience, give
+			// this field a blank name.
+			fld.Names = append(fld.Names, ast.NewIdent("_")) // will be named below
+		}
+	}
+	blanks := 0
+	for id, field := range astutil.FlatFields(params) {
+		if id.Name == "_" && used(len(names)) { // from above: every field has names
+			// Create names for blank (_) parameters so the delegating wrapper
+			// can refer to them.
+			for {
+				// These names will not be seen by the user, so give them an
+				// arbitrary name.
+				newName := fmt.Sprintf("blank%d", blanks)
+				blanks++
+				if !nonBlankNames[newName] {
+					id.Name = newName
+					break
+				}
+			}
+		}
+		names = append(names, id.Name)
+		// Record whether the last parameter is variadic.
+		// (Only the last loop iteration matters.)
+		_, variadic = field.Type.(*ast.Ellipsis)
+	}
+	return params, names, variadic
+}
+
+// documentChanges translates the new contents of a set of files into
+// document changes.
+func documentChanges(ctx context.Context, snapshot *cache.Snapshot, newContent map[protocol.DocumentURI][]byte) ([]protocol.DocumentChange, error) {
 	var changes []protocol.DocumentChange
 	for uri, after := range newContent {
 		fh, err := snapshot.ReadFile(ctx, uri)
@@ -484,6 +490,7 @@ type signatureRewrite struct {
 	params            *ast.FieldList
 	callArgs          []ast.Expr
 	variadic          bool
+	decls             string // additional declarations required by newDecl, if any
 }
 
 // rewriteCalls returns the document changes required to rewrite the
@@ -518,6 +525,8 @@ type signatureRewrite struct {
 //     new wrapper.
 //   - callArgs is the argument list (a, c, blank0), to be used to call the new
 //     delegate.
+//   - decls is empty, as the new declaration requires no other declarations
+//     (see [IntroduceParamObject] for a rewrite that does).
 //
 // rewriting is expressed this way so that rewriteCalls can own the details
 // of *how* this rewriting is performed. For example, as of writing it names
@@ -619,6 +628,9 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 		// TODO(rfindley): we can probably get away with one fewer parse operations
 		// by returning the modified AST from replaceDecl. Investigate if that is
 		// accurate.
+		if rw.decls != "" {
+			modifiedSrc = append(modifiedSrc, []byte("\n\n"+rw.decls)...)
+		}
 		modifiedSrc = append(modifiedSrc, []byte("\n\n"+FormatNode(fset, wrapper))...)
 		modifiedFile, err = parser.ParseFile(rw.pkg.FileSet(), rw.pgf.URI.Path(), modifiedSrc, parser.ParseComments|parser.SkipObjectResolution)
 		if err != nil {
@@ -644,7 +656,14 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 		Logf:          logf,
 		IgnoreEffects: true,
 	}
-	return inlineAllCalls(ctx, rw.snapshot, rw.pkg, rw.pgf, rw.origDecl, calleeInfo, post, opts)
+	result, skipped, err := inlineAllCalls(ctx, rw.snapshot, rw.pkg, rw.pgf, rw.origDecl, calleeInfo, post, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		return nil, fmt.Errorf("cannot update %d call(s) outside the workspace, such as at %s", len(skipped), skipped[0].URI.Path())
+	}
+	return result, nil
 }
 
 // reTypeCheck re-type checks orig with new file contents defined by fileMask.
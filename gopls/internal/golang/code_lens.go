@@ -26,6 +26,7 @@ func CodeLensSources() map[settings.CodeLensSource]cache.CodeLensSourceFunc {
 		settings.CodeLensGenerate:      goGenerateCodeLens, // commands: Generate
 		settings.CodeLensTest:          runTestCodeLens,    // commands: Test
 		settings.CodeLensRegenerateCgo: regenerateCgoLens,  // commands: RegenerateCgo
+		settings.CodeLensCoverage:      coverageCodeLens,   // commands: Coverage
 	}
 }
 
@@ -182,6 +183,32 @@ func goGenerateCodeLens(ctx context.Context, snapshot *cache.Snapshot, fh file.H
 	return nil, nil
 }
 
+func coverageCodeLens(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]protocol.CodeLens, error) {
+	puri := fh.URI()
+	shown := snapshot.Coverage(puri.Dir()) != nil
+	if !shown && !strings.HasSuffix(puri.Path(), "_test.go") {
+		return nil, nil
+	}
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Header)
+	if err != nil {
+		return nil, err
+	}
+	rng, err := pgf.PosRange(pgf.File.Package, pgf.File.Package)
+	if err != nil {
+		return nil, err
+	}
+	var codeLens []protocol.CodeLens
+	if strings.HasSuffix(puri.Path(), "_test.go") {
+		cmd := command.NewCoverageCommand("run tests with coverage", command.CoverageArgs{URI: puri})
+		codeLens = append(codeLens, protocol.CodeLens{Range: rng, Command: cmd})
+	}
+	if shown {
+		cmd := command.NewCoverageCommand("hide coverage", command.CoverageArgs{URI: puri, Hide: true})
+		codeLens = append(codeLens, protocol.CodeLens{Range: rng, Command: cmd})
+	}
+	return codeLens, nil
+}
+
 func regenerateCgoLens(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]protocol.CodeLens, error) {
 	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
 	if err != nil {
@@ -248,6 +248,7 @@ var codeActionProducers = [...]codeActionProducer{
 	{kind: settings.GoTest, fn: goTest, needPkg: true},
 	{kind: settings.GoToggleCompilerOptDetails, fn: toggleCompilerOptDetails},
 	{kind: settings.RefactorExtractFunction, fn: refactorExtractFunction},
+	{kind: settings.RefactorExtractInterface, fn: refactorExtractInterface, needPkg: true},
 	{kind: settings.RefactorExtractMethod, fn: refactorExtractMethod},
 	{kind: settings.RefactorExtractToNewFile, fn: refactorExtractToNewFile},
 	{kind: settings.RefactorExtractConstant, fn: refactorExtractVariable, needPkg: true},
@@ -255,12 +256,16 @@ var codeActionProducers = [...]codeActionProducer{
 	{kind: settings.RefactorExtractConstantAll, fn: refactorExtractVariableAll, needPkg: true},
 	{kind: settings.RefactorExtractVariableAll, fn: refactorExtractVariableAll, needPkg: true},
 	{kind: settings.RefactorInlineCall, fn: refactorInlineCall, needPkg: true},
+	{kind: settings.RefactorInlineCallAll, fn: refactorInlineCallAll, needPkg: true},
 	{kind: settings.RefactorInlineVariable, fn: refactorInlineVariable, needPkg: true},
+	{kind: settings.RefactorMoveDecl, fn: refactorMoveDecl},
 	{kind: settings.RefactorMoveType, fn: refactorMoveType, needPkg: true},
 	{kind: settings.RefactorRewriteChangeQuote, fn: refactorRewriteChangeQuote},
 	{kind: settings.RefactorRewriteFillStruct, fn: refactorRewriteFillStruct, needPkg: true},
 	{kind: settings.RefactorRewriteFillSwitch, fn: refactorRewriteFillSwitch, needPkg: true},
 	{kind: settings.RefactorRewriteImplementInterface, fn: refactorRewriteImplementInterface, needPkg: true},
+	{kind: settings.RefactorRewriteIntroduceParamObject, fn: refactorRewriteIntroduceParamObject, needPkg: true},
+	{kind: settings.RefactorRewriteFunctionalOptions, fn: refactorRewriteFunctionalOptions, needPkg: true},
 	{kind: settings.RefactorRewriteInvertIf, fn: refactorRewriteInvertIf},
 	{kind: settings.RefactorRewriteJoinLines, fn: refactorRewriteJoinLines, needPkg: true},
 	{kind: settings.RefactorRewriteRemoveUnusedParam, fn: refactorRewriteRemoveUnusedParam, needPkg: true},
@@ -501,6 +506,21 @@ func refactorExtractMethod(ctx context.Context, req *codeActionsRequest) error {
 	return nil
 }
 
+// refactorExtractInterface produces "Extract interface" code actions.
+// See [extractInterface] for command implementation.
+func refactorExtractInterface(ctx context.Context, req *codeActionsRequest) error {
+	x, err := canExtractInterface(req.pkg, req.pgf, req.start, req.end)
+	if err != nil {
+		return nil
+	}
+	title := fmt.Sprintf("Extract interface from %s", x.named.Obj().Name())
+	if x.param != nil {
+		title = fmt.Sprintf("Extract interface for parameter %s", x.param.Names[0].Name)
+	}
+	req.addApplyFixAction(title, fixExtractInterface, req.loc)
+	return nil
+}
+
 // refactorExtractVariable produces "Extract variable|constant" code actions.
 // See [extractVariable] for command implementation.
 func refactorExtractVariable(ctx context.Context, req *codeActionsRequest) error {
@@ -681,6 +701,42 @@ func refactorRewriteMoveParamRight(ctx context.Context, req *codeActionsRequest)
 	return nil
 }
 
+// refactorRewriteIntroduceParamObject produces "Introduce parameter object"
+// code actions.
+// See [server.commandHandler.IntroduceParamObject] for command implementation.
+func refactorRewriteIntroduceParamObject(ctx context.Context, req *codeActionsRequest) error {
+	if _, err := selectParams(req.pgf, req.start, req.end, 2); err == nil {
+		cmd := command.NewIntroduceParamObjectCommand("Introduce parameter object", command.IntroduceParamObjectArgs{
+			Location:     req.loc,
+			ResolveEdits: req.resolveEdits(),
+		})
+		req.addCommandAction(cmd, true)
+	}
+	return nil
+}
+
+// refactorRewriteFunctionalOptions produces "Convert {to,from} functional
+// options" code actions.
+// See [server.commandHandler.ConvertToFunctionalOptions] and
+// [server.commandHandler.ConvertFromFunctionalOptions] for command implementation.
+func refactorRewriteFunctionalOptions(ctx context.Context, req *codeActionsRequest) error {
+	if _, err := selectOptionParams(req.pgf, req.start, req.end); err == nil {
+		cmd := command.NewConvertToFunctionalOptionsCommand("Convert to functional options", command.ConvertToFunctionalOptionsArgs{
+			Location:     req.loc,
+			ResolveEdits: req.resolveEdits(),
+		})
+		req.addCommandAction(cmd, true)
+	}
+	if _, err := matchOptionsDecl(req.pkg, req.pgf, req.start, req.end); err == nil {
+		cmd := command.NewConvertFromFunctionalOptionsCommand("Convert from functional options", command.ConvertFromFunctionalOptionsArgs{
+			Location:     req.loc,
+			ResolveEdits: req.resolveEdits(),
+		})
+		req.addCommandAction(cmd, true)
+	}
+	return nil
+}
+
 // refactorRewriteChangeQuote produces "Convert to {raw,interpreted} string literal" code actions.
 func refactorRewriteChangeQuote(ctx context.Context, req *codeActionsRequest) error {
 	convertStringLiteral(req)
@@ -1093,6 +1149,24 @@ func refactorInlineCall(ctx context.Context, req *codeActionsRequest) error {
 	return nil
 }
 
+// refactorInlineCallAll produces "Inline all calls to FUNC" code actions.
+// See [server.commandHandler.InlineAllCalls] for command implementation.
+func refactorInlineCallAll(ctx context.Context, req *codeActionsRequest) error {
+	// As with refactorInlineCall, offer this only after a selection or
+	// explicit menu operation.
+	if req.trigger == protocol.CodeActionAutomatic && req.loc.Empty() {
+		return nil
+	}
+
+	if _, fn, err := enclosingStaticCall(req.pkg, req.pgf, req.start, req.end); err == nil {
+		cmd := command.NewInlineAllCallsCommand("Inline all calls to "+fn.Name(), command.InlineAllCallsArgs{
+			Location: req.loc,
+		})
+		req.addCommandAction(cmd, false)
+	}
+	return nil
+}
+
 // refactorInlineVariable produces the "Inline variable 'v'" code action.
 // See [inlineVariableOne] for command implementation.
 func refactorInlineVariable(ctx context.Context, req *codeActionsRequest) error {
@@ -1246,6 +1320,50 @@ func toggleCompilerOptDetails(ctx context.Context, req *codeActionsRequest) erro
 	return nil
 }
 
+// refactorMoveDecl produces "Move X to another package..." code actions.
+// See [server.commandHandler.MoveDecl] for command implementation.
+func refactorMoveDecl(_ context.Context, req *codeActionsRequest) error {
+	if !req.snapshot.Options().MoveDecl {
+		return nil
+	}
+	// The destination is provided by the user through a dialog.
+	if !supportsDialog(req.snapshot.Options().ClientOptions, moveDeclFormFile, moveDeclFormString) {
+		return nil
+	}
+	if strings.HasSuffix(string(req.fh.URI()), "_test.go") {
+		return nil
+	}
+	decl, specs, err := declToMove(req.pgf, req.start, req.end)
+	if err != nil {
+		return nil
+	}
+	var what string
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		what = "func " + decl.Name.Name
+	case *ast.GenDecl:
+		what = decl.Tok.String() + " declaration"
+		if len(specs) == 1 {
+			switch spec := specs[0].(type) {
+			case *ast.TypeSpec:
+				what = decl.Tok.String() + " " + spec.Name.Name
+			case *ast.ValueSpec:
+				var names []string
+				for _, id := range spec.Names {
+					names = append(names, id.Name)
+				}
+				what = decl.Tok.String() + " " + strings.Join(names, ", ")
+			}
+		}
+	}
+	cmd := command.NewMoveDeclCommand(fmt.Sprintf("Move %s to another package...", what), command.MoveDeclArgs{
+		Location: req.loc,
+		// Dest will be provided by the user through dialog.
+	})
+	req.addCommandAction(cmd, false)
+	return nil
+}
+
 func refactorMoveType(_ context.Context, req *codeActionsRequest) error {
 	if !req.snapshot.Options().MoveType {
 		return nil
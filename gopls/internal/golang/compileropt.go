@@ -19,11 +19,37 @@ import (
 	"golang.org/x/tools/internal/event"
 )
 
-// CompilerOptDetails invokes the Go compiler with the "-json=0,dir"
-// flag on the packages and tests in the specified directory, parses
-// its log of optimization decisions, and returns them as a set of
-// diagnostics.
+// CompilerOptDetails returns the compiler's optimization decisions
+// for the packages and tests in the specified directory, as a set of
+// diagnostics, filtered according to the "annotations" setting.
 func CompilerOptDetails(ctx context.Context, snapshot *cache.Snapshot, pkgDir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	log, err := compilerOptLog(ctx, snapshot, pkgDir)
+	reports := make(map[protocol.DocumentURI][]*cache.Diagnostic)
+	for uri, diagnostics := range log {
+		var shown []*cache.Diagnostic
+		for _, diag := range diagnostics {
+			if showDiagnostic(diag.Message, snapshot.Options()) {
+				shown = append(shown, diag)
+			}
+		}
+		reports[uri] = shown
+	}
+	return reports, err
+}
+
+// compilerOptLog returns the compiler's log of optimization decisions
+// for the packages and tests in the specified directory, as a set of
+// diagnostics, memoized by the snapshot.
+func compilerOptLog(ctx context.Context, snapshot *cache.Snapshot, pkgDir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	return snapshot.CompilerOptLog(ctx, pkgDir, func(ctx context.Context, snapshot *cache.Snapshot) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+		return runCompilerOptLog(ctx, snapshot, pkgDir)
+	})
+}
+
+// runCompilerOptLog invokes the Go compiler with the "-json=0,dir"
+// flag on the packages and tests in the specified directory, and
+// parses its log of optimization decisions.
+func runCompilerOptLog(ctx context.Context, snapshot *cache.Snapshot, pkgDir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
 	outDir, err := os.MkdirTemp("", fmt.Sprintf("gopls-%d.details", os.Getpid()))
 	if err != nil {
 		return nil, err
@@ -66,7 +92,7 @@ func CompilerOptDetails(ctx context.Context, snapshot *cache.Snapshot, pkgDir pr
 	reports := make(map[protocol.DocumentURI][]*cache.Diagnostic)
 	var parseError error
 	for _, fn := range files {
-		uri, diagnostics, err := parseDetailsFile(fn, snapshot.Options())
+		uri, diagnostics, err := parseDetailsFile(fn)
 		if err != nil {
 			// expect errors for all the files, save 1
 			parseError = err
@@ -88,7 +114,10 @@ func CompilerOptDetails(ctx context.Context, snapshot *cache.Snapshot, pkgDir pr
 }
 
 // parseDetailsFile parses the file written by the Go compiler which contains a JSON-encoded protocol.Diagnostic.
-func parseDetailsFile(filename string, options *settings.Options) (protocol.DocumentURI, []*cache.Diagnostic, error) {
+//
+// The message of each diagnostic has the form "code(message)", for
+// example "escape(x escapes to heap)"; see [splitDecision].
+func parseDetailsFile(filename string) (protocol.DocumentURI, []*cache.Diagnostic, error) {
 	buf, err := os.ReadFile(filename)
 	if err != nil {
 		return "", nil, err
@@ -124,7 +153,7 @@ func parseDetailsFile(filename string, options *settings.Options) (protocol.Docu
 		if msg != "" {
 			msg = fmt.Sprintf("%s(%s)", msg, d.Message)
 		}
-		if !showDiagnostic(msg, d.Source, options) {
+		if d.Source != "go compiler" {
 			continue
 		}
 
@@ -170,10 +199,7 @@ func parseDetailsFile(filename string, options *settings.Options) (protocol.Docu
 
 // showDiagnostic reports whether a given diagnostic should be shown to the end
 // user, given the current options.
-func showDiagnostic(msg, source string, o *settings.Options) bool {
-	if source != "go compiler" {
-		return false
-	}
+func showDiagnostic(msg string, o *settings.Options) bool {
 	if o.Annotations == nil {
 		return true
 	}
@@ -213,6 +239,17 @@ func showDiagnostic(msg, source string, o *settings.Options) bool {
 	return false
 }
 
+// splitDecision splits the message of a diagnostic returned by
+// parseDetailsFile into the code of the compiler's decision and its
+// details, for example "escape" and "x escapes to heap".
+func splitDecision(msg string) (code, details string) {
+	code, details, ok := strings.Cut(msg, "(")
+	if !ok {
+		return msg, ""
+	}
+	return code, strings.TrimSuffix(details, ")")
+}
+
 func findJSONFiles(dir string) ([]string, error) {
 	ans := []string{}
 	f := func(path string, fi os.FileInfo, _ error) error {
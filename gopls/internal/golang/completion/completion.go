@@ -102,6 +102,12 @@ type CompletionItem struct {
 	// Documentation is the documentation for the completion item.
 	Documentation string
 
+	// Resolve, if non-nil, holds the information from which a
+	// completionItem/resolve request computes the properties of the
+	// item that were deferred: its documentation, and the edits that
+	// import the package of an unimported candidate.
+	Resolve *ResolveData
+
 	// isSlice reports whether the underlying type of the object
 	// from which this candidate was derived is a slice.
 	// (Used to complete append() calls.)
@@ -112,13 +118,14 @@ type CompletionItem struct {
 type completionOptions struct {
 	unimported            bool
 	documentation         bool
-	fullDocumentation     bool
 	placeholders          bool
 	snippets              bool
 	postfix               bool
 	matcher               settings.Matcher
 	budget                time.Duration
 	completeFunctionCalls bool
+	resolveDocumentation  bool // defer documentation to completionItem/resolve
+	resolveImports        bool // defer import edits to completionItem/resolve
 }
 
 // Snippet is a convenience returns the snippet if available, otherwise
@@ -635,12 +642,13 @@ func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 			matcher:               opts.Matcher,
 			unimported:            opts.CompleteUnimported,
 			documentation:         opts.CompletionDocumentation && opts.HoverKind != settings.NoDocumentation,
-			fullDocumentation:     opts.HoverKind == settings.FullDocumentation,
 			placeholders:          opts.UsePlaceholders,
 			budget:                opts.CompletionBudget,
 			snippets:              opts.InsertTextFormat == protocol.SnippetTextFormat,
 			postfix:               opts.ExperimentalPostfixCompletions,
 			completeFunctionCalls: opts.CompleteFunctionCalls,
+			resolveDocumentation:  slices.Contains(opts.CompletionResolveOptions, "documentation"),
+			resolveImports:        slices.Contains(opts.CompletionResolveOptions, "additionalTextEdits"),
 		},
 		// default to a matcher that always matches
 		matcher:            prefixMatcher(""),
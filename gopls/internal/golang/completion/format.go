@@ -9,7 +9,6 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
-	"go/doc"
 	"go/types"
 	"strings"
 
@@ -18,7 +17,6 @@ import (
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/util/safetoken"
 	"golang.org/x/tools/gopls/internal/util/typesutil"
-	internalastutil "golang.org/x/tools/internal/astutil"
 	"golang.org/x/tools/internal/event"
 	"golang.org/x/tools/internal/imports"
 )
@@ -182,14 +180,17 @@ Suffixes:
 
 	// If this candidate needs an additional import statement,
 	// add the additional text edits needed.
+	var resolve ResolveData
 	if cand.imp != nil {
-		addlEdits, err := c.importEdits(cand.imp)
-
-		if err != nil {
-			return CompletionItem{}, err
+		if c.opts.resolveImports {
+			resolve.ImportPath, resolve.ImportName = cand.imp.importPath, cand.imp.name
+		} else {
+			addlEdits, err := c.importEdits(cand.imp)
+			if err != nil {
+				return CompletionItem{}, err
+			}
+			protocolEdits = append(protocolEdits, addlEdits...)
 		}
-
-		protocolEdits = append(protocolEdits, addlEdits...)
 		if kind != protocol.ModuleCompletion {
 			if detail != "" {
 				detail += " "
@@ -248,37 +249,53 @@ Suffixes:
 		snippet:             &snip,
 		isSlice:             isSlice(obj),
 	}
-	// If the user doesn't want documentation for completion items.
-	if !c.opts.documentation {
-		return item, nil
+	if c.opts.documentation {
+		c.addDocumentation(ctx, &item, obj, &resolve)
+	}
+	if resolve != (ResolveData{}) {
+		resolve.URI = protocol.URIFromPath(c.filename)
+		item.Resolve = &resolve
 	}
+	return item, nil
+}
+
+// addDocumentation adds to the item the documentation of obj, and the
+// deprecation tags derived from it. If the client supports it, it
+// instead records in resolve the declaration of obj, from which
+// completionItem/resolve computes the documentation, without reading
+// the declaring file. In that case the tags are added only if obj is
+// declared in the syntax of the current package, so that clients may
+// mark deprecated items in the list; otherwise they too are resolved.
+func (c *completer) addDocumentation(ctx context.Context, item *CompletionItem, obj types.Object, resolve *ResolveData) {
 	pos := safetoken.StartPosition(c.pkg.FileSet(), obj.Pos())
 
 	// We ignore errors here, because some types, like "unsafe" or "error",
 	// may not have valid positions that we can use to get documentation.
 	if !pos.IsValid() {
-		return item, nil
+		return
+	}
+
+	if c.opts.resolveDocumentation {
+		if _, ok := obj.(*types.TypeName); ok && is[*types.TypeParam](obj.Type()) {
+			return // type parameters have no documentation
+		}
+		uri := protocol.URIFromPath(pos.Filename)
+		resolve.DeclURI, resolve.DeclOffset = uri, pos.Offset
+		if obj.Pkg() == c.pkg.Types() {
+			if pgf, err := c.pkg.File(uri); err == nil {
+				item.Tags, item.Deprecated = deprecation(golang.HoverDocInFile(pgf, obj.Pos()), c.snapshot.Options())
+			}
+		}
+		return
 	}
 
 	comment, err := golang.HoverDocForObject(ctx, c.snapshot, c.pkg.FileSet(), obj)
 	if err != nil {
 		event.Error(ctx, fmt.Sprintf("failed to find Hover for %q", obj.Name()), err)
-		return item, nil
-	}
-	if c.opts.fullDocumentation {
-		item.Documentation = comment.Text()
-	} else {
-		item.Documentation = doc.Synopsis(comment.Text())
+		return
 	}
-	if internalastutil.Deprecation(comment) != "" {
-		if c.snapshot.Options().CompletionTags {
-			item.Tags = []protocol.CompletionItemTag{protocol.ComplDeprecated}
-		} else if c.snapshot.Options().CompletionDeprecated {
-			item.Deprecated = true
-		}
-	}
-
-	return item, nil
+	item.Tags, item.Deprecated = deprecation(comment, c.snapshot.Options())
+	item.Documentation = documentation(comment, c.snapshot.Options())
 }
 
 // conversionEdits represents the string edits needed to make a type conversion
@@ -324,7 +341,13 @@ func (c *completer) importEdits(imp *importInfo) ([]protocol.TextEdit, error) {
 		return nil, err
 	}
 
-	return golang.ComputeImportFixEdits(c.snapshot.Options().Local, pgf.Src, &imports.ImportFix{
+	return addImportEdits(c.snapshot.Options().Local, pgf.Src, imp)
+}
+
+// addImportEdits returns the text edits that add the given import to
+// the file with content src.
+func addImportEdits(local string, src []byte, imp *importInfo) ([]protocol.TextEdit, error) {
+	return golang.ComputeImportFixEdits(local, src, &imports.ImportFix{
 		StmtInfo: imports.ImportInfo{
 			ImportPath: imp.importPath,
 			Name:       imp.name,
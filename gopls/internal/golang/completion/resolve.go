@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completion
+
+// This file defines the properties of completion items that are
+// computed lazily by completionItem/resolve.
+
+import (
+	"context"
+	"go/ast"
+	"go/doc"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+	internalastutil "golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/event"
+)
+
+// ResolveData holds the information, recorded in the Data field of a
+// completion item, from which completionItem/resolve computes the
+// properties of the item that were deferred.
+type ResolveData struct {
+	URI protocol.DocumentURI `json:"uri"` // file in which completion was requested
+
+	// The declaration of the candidate's object, whose doc comment
+	// provides the item's documentation.
+	DeclURI    protocol.DocumentURI `json:"declURI,omitempty"`
+	DeclOffset int                  `json:"declOffset,omitempty"`
+
+	// The package to import for an unimported candidate.
+	ImportPath string `json:"importPath,omitempty"`
+	ImportName string `json:"importName,omitempty"`
+}
+
+// ResolvedItem holds the deferred properties of a completion item.
+type ResolvedItem struct {
+	Documentation       string
+	Tags                []protocol.CompletionItemTag // nil unless the documentation was resolved
+	Deprecated          bool
+	AdditionalTextEdits []protocol.TextEdit // to be added to those of the item
+}
+
+// Resolve computes the properties of a completion item that were
+// deferred to a completionItem/resolve request.
+func Resolve(ctx context.Context, snapshot *cache.Snapshot, data *ResolveData) (*ResolvedItem, error) {
+	ctx, done := event.Start(ctx, "completion.Resolve")
+	defer done()
+
+	var res ResolvedItem
+	if data.DeclURI != "" {
+		comment, err := golang.HoverDocAt(ctx, snapshot, data.DeclURI, data.DeclOffset)
+		if err != nil {
+			return nil, err
+		}
+		res.Documentation = documentation(comment, snapshot.Options())
+		res.Tags, res.Deprecated = deprecation(comment, snapshot.Options())
+	}
+	if data.ImportPath != "" {
+		fh, err := snapshot.ReadFile(ctx, data.URI)
+		if err != nil {
+			return nil, err
+		}
+		pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+		if err != nil {
+			return nil, err
+		}
+		edits, err := addImportEdits(snapshot.Options().Local, pgf.Src, &importInfo{
+			importPath: data.ImportPath,
+			name:       data.ImportName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		res.AdditionalTextEdits = edits
+	}
+	return &res, nil
+}
+
+// documentation returns the documentation of a completion item whose
+// object has the given doc comment.
+func documentation(comment *ast.CommentGroup, opts *settings.Options) string {
+	text := comment.Text()
+	if opts.HoverKind != settings.FullDocumentation {
+		text = doc.Synopsis(text)
+	}
+	return text
+}
+
+// deprecation returns the deprecation tags of a completion item whose
+// object has the given doc comment.
+func deprecation(comment *ast.CommentGroup, opts *settings.Options) (tags []protocol.CompletionItemTag, deprecated bool) {
+	if internalastutil.Deprecation(comment) != "" {
+		if opts.CompletionTags {
+			tags = []protocol.CompletionItemTag{protocol.ComplDeprecated}
+		} else if opts.CompletionDeprecated {
+			deprecated = true
+		}
+	}
+	return tags, deprecated
+}
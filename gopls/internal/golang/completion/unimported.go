@@ -359,7 +359,15 @@ func (c *completer) appendNewItem(got []CompletionItem, name, detail string, pat
 	if imports.ImportPathToAssumedName(string(path)) == string(pkg) {
 		imp.name = ""
 	}
-	item.AdditionalTextEdits, _ = c.importEdits(&imp)
+	if c.opts.resolveImports {
+		item.Resolve = &ResolveData{
+			URI:        protocol.URIFromPath(c.filename),
+			ImportPath: imp.importPath,
+			ImportName: imp.name,
+		}
+	} else {
+		item.AdditionalTextEdits, _ = c.importEdits(&imp)
+	}
 	if params != nil {
 		var sn snippet.Builder
 		c.functionCallSnippet(name, nil, params, &sn)
@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the coverage overlay: the report of the statements
+// not covered by a test coverage profile, as diagnostics, and of the
+// coverage of each function, in hover.
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/cover"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// DefaultCoverageProfile returns the file, within the temporary
+// directory of the session, to which the coverage profile of the
+// tests of the packages in dir is written when gopls runs them.
+func DefaultCoverageProfile(session *cache.Session, dir protocol.DocumentURI) (protocol.DocumentURI, error) {
+	tempDir, err := session.TempDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(dir))
+	return protocol.URIFromPath(filepath.Join(tempDir, "coverage", fmt.Sprintf("%x.out", sum[:8]))), nil
+}
+
+// RunCoverage runs the tests of the packages in dir with coverage
+// enabled, writing the coverage profile to the specified file, and
+// the output of go test to out.
+func RunCoverage(ctx context.Context, snapshot *cache.Snapshot, dir, profile protocol.DocumentURI, out io.Writer) error {
+	if err := os.MkdirAll(profile.DirPath(), 0o777); err != nil {
+		return err
+	}
+	inv, cleanupInvocation, err := snapshot.GoCommandInvocation(cache.NoNetwork, dir.Path(), "test", []string{
+		"-count=1",
+		"-coverprofile=" + profile.Path(),
+		".",
+	})
+	if err != nil {
+		return err
+	}
+	defer cleanupInvocation()
+	return snapshot.View().GoCommandRunner().RunPiped(ctx, *inv, out, out)
+}
+
+// CoverageDiagnostics returns a diagnostic of information severity
+// for each block of statements of the package mp that is not covered
+// according to the coverage profile for its directory.
+func CoverageDiagnostics(ctx context.Context, snapshot *cache.Snapshot, mp *metadata.Package) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	reports := make(map[protocol.DocumentURI][]*cache.Diagnostic)
+	for _, uri := range mp.CompiledGoFiles {
+		blocks, content, err := fileCoverage(ctx, snapshot, mp.PkgPath, uri)
+		if err != nil {
+			return nil, err
+		}
+		if blocks == nil {
+			continue
+		}
+		m := protocol.NewMapper(uri, content)
+		var diags []*cache.Diagnostic
+		for _, b := range blocks {
+			if b.Count > 0 || b.NumStmt == 0 {
+				continue
+			}
+			start, err := m.LineCol8Position(b.StartLine, b.StartCol)
+			if err != nil {
+				continue // profile is stale
+			}
+			end, err := m.LineCol8Position(b.EndLine, b.EndCol)
+			if err != nil {
+				continue
+			}
+			msg := "1 statement not covered by tests"
+			if b.NumStmt > 1 {
+				msg = fmt.Sprintf("%d statements not covered by tests", b.NumStmt)
+			}
+			diags = append(diags, &cache.Diagnostic{
+				URI:      uri,
+				Range:    protocol.Range{Start: start, End: end},
+				Severity: protocol.SeverityInformation,
+				Source:   cache.CoverageInfo,
+				Message:  msg,
+			})
+		}
+		reports[uri] = diags
+	}
+	return reports, nil
+}
+
+// fileCoverage returns the coverage blocks of the specified file of
+// package pkgPath, and its content, from the coverage profile for its
+// directory. It returns no blocks if there is no profile, or if the
+// file has unsaved changes, as the profile no longer describes it.
+func fileCoverage(ctx context.Context, snapshot *cache.Snapshot, pkgPath PackagePath, uri protocol.DocumentURI) ([]cover.ProfileBlock, []byte, error) {
+	c := snapshot.Coverage(uri.Dir())
+	if c == nil {
+		return nil, nil, nil
+	}
+	blocks, err := c.FileBlocks(pkgPath, uri.Base())
+	if err != nil || blocks == nil {
+		return nil, nil, err
+	}
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !fh.SameContentsOnDisk() {
+		return nil, nil, nil
+	}
+	content, err := fh.Content()
+	if err != nil {
+		return nil, nil, nil
+	}
+	return blocks, content, nil
+}
+
+// funcCoverage returns a description of the coverage of the statements
+// of the declaration of function fn, or "" if there is no coverage
+// profile for it.
+func funcCoverage(ctx context.Context, snapshot *cache.Snapshot, fset *token.FileSet, fn *types.Func) (string, error) {
+	if fn.Pkg() == nil {
+		return "", nil
+	}
+	// Avoid parsing the file if there is no profile for it.
+	if f := fset.File(fn.Pos()); f == nil || snapshot.Coverage(protocol.URIFromPath(f.Name()).Dir()) == nil {
+		return "", nil
+	}
+	pgf, pos, err := parseFull(ctx, snapshot, fset, fn)
+	if err != nil {
+		return "", err
+	}
+	blocks, _, err := fileCoverage(ctx, snapshot, PackagePath(fn.Pkg().Path()), pgf.URI)
+	if err != nil || blocks == nil {
+		return "", err
+	}
+	for _, decl := range pgf.File.Decls {
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok || decl.Name.Pos() != pos || decl.Body == nil {
+			continue
+		}
+		// A block belongs to the function if it lies within its body.
+		// (This is the criterion of "go tool cover -func".)
+		start, end := pgf.Tok.Position(decl.Body.Pos()), pgf.Tok.Position(decl.Body.End())
+		var total, covered int
+		for _, b := range blocks {
+			if before(b.StartLine, b.StartCol, start) || after(b.EndLine, b.EndCol, end) {
+				continue
+			}
+			total += b.NumStmt
+			if b.Count > 0 {
+				covered += b.NumStmt
+			}
+		}
+		if total == 0 {
+			return "", nil
+		}
+		return fmt.Sprintf("Test coverage: %.1f%% of statements", 100*float64(covered)/float64(total)), nil
+	}
+	return "", nil
+}
+
+// before reports whether the 1-based (line, col) position precedes posn.
+func before(line, col int, posn token.Position) bool {
+	return line < posn.Line || line == posn.Line && col < posn.Column
+}
+
+// after reports whether the 1-based (line, col) position follows posn.
+func after(line, col int, posn token.Position) bool {
+	return line > posn.Line || line == posn.Line && col > posn.Column
+}
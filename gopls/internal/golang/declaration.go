@@ -0,0 +1,115 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+	"slices"
+	"sync"
+
+	"golang.org/x/tools/go/ast/edge"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/methodsets"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// Declaration handles the textDocument/declaration request for Go files.
+//
+// Whereas Definition reports the concrete declaration of a symbol,
+// Declaration reports its abstract declaration, where there is one:
+//
+//   - for a method of a concrete type, the methods of the interfaces
+//     that it satisfies;
+//   - for a field promoted through an embedded field, that embedded
+//     field, in the struct type of the selection's operand.
+//
+// Otherwise it reports the same locations as Definition.
+func Declaration(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range) ([]protocol.Location, error) {
+	ctx, done := event.Start(ctx, "golang.Declaration")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	cur, _ := pgf.Cursor().FindByPos(start, end) // can't fail
+
+	if id, ok := cur.Node().(*ast.Ident); ok {
+		info := pkg.TypesInfo()
+
+		// A promoted field x.f is declared by the embedded
+		// field through which it is selected.
+		if cur.ParentEdgeKind() == edge.SelectorExpr_Sel {
+			sel := info.Selections[cur.Parent().Node().(*ast.SelectorExpr)]
+			if sel != nil && sel.Kind() == types.FieldVal && len(sel.Index()) > 1 {
+				if field := embeddedField(sel.Recv(), sel.Index()[0]); field != nil {
+					loc, err := ObjectLocation(ctx, pkg.FileSet(), snapshot, field)
+					if err != nil {
+						return nil, err
+					}
+					return []protocol.Location{loc}, nil
+				}
+			}
+		}
+
+		// A concrete method is declared by the interface methods it satisfies.
+		if fn, ok := info.ObjectOf(id).(*types.Func); ok {
+			if recv := fn.Signature().Recv(); recv != nil && !types.IsInterface(recv.Type()) {
+				locs, err := abstractMethods(ctx, snapshot, pkg, cur)
+				if err != nil {
+					return nil, err
+				}
+				if len(locs) > 0 {
+					return locs, nil
+				}
+			}
+		}
+	}
+
+	return Definition(ctx, snapshot, fh, rng)
+}
+
+// embeddedField returns the i'th field of the struct type, or pointer
+// to struct type, t, if it is embedded.
+func embeddedField(t types.Type, i int) *types.Var {
+	if st, ok := typesinternal.Unpointer(t).Underlying().(*types.Struct); ok && i < st.NumFields() {
+		if field := st.Field(i); field.Embedded() {
+			return field
+		}
+	}
+	return nil
+}
+
+// abstractMethods returns the sorted locations of the interface methods
+// that are satisfied by the concrete method identified by cur.
+func abstractMethods(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, cur inspector.Cursor) ([]protocol.Location, error) {
+	var (
+		mu   sync.Mutex
+		locs []protocol.Location
+	)
+	err := implementationsMsets(ctx, snapshot, pkg, cur, methodsets.Supertype, func(_ metadata.PackagePath, _ string, abstract bool, loc protocol.Location) {
+		if abstract {
+			mu.Lock()
+			locs = append(locs, loc)
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(locs, protocol.CompareLocation)
+	return slices.Compact(locs), nil
+}
@@ -0,0 +1,460 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "Extract interface" code action
+// (refactor.extract.interface).
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/edge"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/methodsets"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/moreiters"
+	"golang.org/x/tools/internal/refactor"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// An interfaceExtraction describes the extraction of an interface
+// from the methods of a concrete named type that are used either in
+// its package or, on a parameter of that type, in a function.
+type interfaceExtraction struct {
+	named    *types.Named  // the concrete type
+	methods  []*types.Func // the used methods of named, in order
+	decl     ast.Decl      // the type declaration or function
+	existing string        // name of an existing interface with exactly these methods, if any
+
+	// For a parameter:
+	param    *ast.Field       // the parameter whose type is changed, or nil
+	paramVar *types.Var       // the parameter
+	fn       *types.Func      // the function declaring the parameter
+	pkgNames []*types.PkgName // package names referenced by the parameter's type
+}
+
+// canExtractInterface reports whether an interface can be extracted
+// from the declaration selected by [start, end): either the name of
+// a type declaration, for which the interface has the methods of the
+// type used in the package; or the name of a function parameter whose
+// type is a named type T or *T, for which the interface has the
+// methods called on the parameter, and replaces its type.
+//
+// It inspects only the selected declaration, so that it is cheap
+// enough to call for each code action request. The checks that
+// require the whole package, or its importers, are made by
+// [extractInterface], which also computes the methods of a type and
+// the existing interface, if any.
+func canExtractInterface(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*interfaceExtraction, error) {
+	cur, ok := pgf.Cursor().FindByPos(start, end)
+	if !ok {
+		return nil, fmt.Errorf("no enclosing syntax")
+	}
+	id, ok := cur.Node().(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("not an identifier")
+	}
+	info := pkg.TypesInfo()
+
+	var x *interfaceExtraction
+	switch obj := info.Defs[id].(type) {
+	case *types.TypeName:
+		curDecl := cur.Parent().Parent()
+		if cur.ParentEdgeKind() != edge.TypeSpec_Name || curDecl.ParentEdgeKind() != edge.File_Decls {
+			return nil, fmt.Errorf("not a package-level type")
+		}
+		named, err := concreteNamed(obj.Type())
+		if err != nil {
+			return nil, err
+		}
+		x = &interfaceExtraction{
+			named: named,
+			decl:  curDecl.Node().(ast.Decl),
+		}
+
+	case *types.Var:
+		var err error
+		if x, err = paramExtraction(pkg, cur, obj); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("not a type or parameter declaration")
+	}
+	return x, nil
+}
+
+// concreteNamed returns the named non-interface type t, which must be
+// neither generic nor an instantiation of a generic type.
+func concreteNamed(t types.Type) (*types.Named, error) {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || types.IsInterface(named) {
+		return nil, fmt.Errorf("not a concrete named type")
+	}
+	if named.TypeParams() != nil || named.TypeArgs() != nil {
+		return nil, fmt.Errorf("generic types are not supported")
+	}
+	return named, nil
+}
+
+// typeMethodsUsed returns the methods of named that are selected in
+// package pkg, other than within the methods of named itself.
+func typeMethodsUsed(pkg *cache.Package, named *types.Named) []*types.Func {
+	info := pkg.TypesInfo()
+	used := make(map[*types.Func]bool)
+	for _, pgf := range pkg.CompiledGoFiles() {
+		for cur := range pgf.Cursor().Preorder((*ast.SelectorExpr)(nil)) {
+			sel := info.Selections[cur.Node().(*ast.SelectorExpr)]
+			if sel == nil || sel.Kind() == types.FieldVal ||
+				!types.Identical(typesinternal.Unpointer(sel.Recv()), named) {
+				continue
+			}
+			if curFunc, ok := moreiters.First(cur.Enclosing((*ast.FuncDecl)(nil))); ok {
+				if fn, ok := info.Defs[curFunc.Node().(*ast.FuncDecl).Name].(*types.Func); ok {
+					if recv := fn.Signature().Recv(); recv != nil && types.Identical(typesinternal.Unpointer(recv.Type()), named) {
+						continue // within a method of named
+					}
+				}
+			}
+			used[sel.Obj().(*types.Func)] = true
+		}
+	}
+	return sortedMethods(pkg.Types(), named, used)
+}
+
+// paramExtraction returns the extraction of an interface for the
+// parameter v declared by the identifier at cur, which must be used
+// within its function only as the operand of method selections.
+//
+// Methods are not supported, since changing the type of a parameter
+// of a method may prevent its type from satisfying an interface.
+func paramExtraction(pkg *cache.Package, cur inspector.Cursor, v *types.Var) (*interfaceExtraction, error) {
+	curField := cur.Parent()
+	if cur.ParentEdgeKind() != edge.Field_Names ||
+		curField.ParentEdgeKind() != edge.FieldList_List ||
+		curField.Parent().ParentEdgeKind() != edge.FuncType_Params ||
+		curField.Parent().Parent().ParentEdgeKind() != edge.FuncDecl_Type {
+		return nil, fmt.Errorf("not a parameter of a function declaration")
+	}
+	field := curField.Node().(*ast.Field)
+	decl := curField.Parent().Parent().Parent().Node().(*ast.FuncDecl)
+	if decl.Recv != nil {
+		return nil, fmt.Errorf("parameters of methods are not supported")
+	}
+	if len(field.Names) != 1 {
+		return nil, fmt.Errorf("parameter %s shares its type with other parameters", v.Name())
+	}
+	if decl.Body == nil {
+		return nil, fmt.Errorf("function has no body")
+	}
+	named, err := concreteNamed(typesinternal.Unpointer(v.Type()))
+	if err != nil {
+		return nil, err
+	}
+
+	info := pkg.TypesInfo()
+	x := &interfaceExtraction{
+		named:    named,
+		decl:     decl,
+		param:    field,
+		paramVar: v,
+		fn:       info.Defs[decl.Name].(*types.Func),
+	}
+
+	// The parameter must be used only as the operand of a method
+	// selection, for a method in the method set of its type.
+	mset := types.NewMethodSet(v.Type())
+	used := make(map[*types.Func]bool)
+	for curId := range cur.Parent().Parent().Parent().Parent().Preorder((*ast.Ident)(nil)) {
+		id := curId.Node().(*ast.Ident)
+		if info.Uses[id] != v {
+			continue
+		}
+		var sel *types.Selection
+		if curId.ParentEdgeKind() == edge.SelectorExpr_X {
+			sel = info.Selections[curId.Parent().Node().(*ast.SelectorExpr)]
+		}
+		if sel == nil || sel.Kind() != types.MethodVal {
+			return nil, fmt.Errorf("parameter %s is used other than as the receiver of a method", v.Name())
+		}
+		method := sel.Obj().(*types.Func)
+		if mset.Lookup(method.Pkg(), method.Name()) == nil {
+			return nil, fmt.Errorf("method %s requires a pointer receiver", method.Name())
+		}
+		used[method] = true
+	}
+	x.methods = sortedMethods(pkg.Types(), named, used)
+	if len(x.methods) == 0 {
+		return nil, fmt.Errorf("no methods are called on %s", v.Name())
+	}
+
+	// Record the packages named by the type of the parameter,
+	// whose imports may become unused.
+	ast.Inspect(field.Type, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if pkgName, ok := info.Uses[id].(*types.PkgName); ok {
+				x.pkgNames = append(x.pkgNames, pkgName)
+			}
+		}
+		return true
+	})
+	return x, nil
+}
+
+// checkOnlyCalled reports an error if the package-level function fn
+// is referenced in package pkg, which may be a different type-checked
+// package than that of fn, other than as the callee of a call.
+func checkOnlyCalled(pkg *cache.Package, fn *types.Func) error {
+	info := pkg.TypesInfo()
+	for _, pgf := range pkg.CompiledGoFiles() {
+		for curId := range pgf.Cursor().Preorder((*ast.Ident)(nil)) {
+			obj, ok := info.Uses[curId.Node().(*ast.Ident)].(*types.Func)
+			if !ok || obj.Pkg() == nil || obj.Pkg().Path() != fn.Pkg().Path() ||
+				obj.Name() != fn.Name() || obj.Signature().Recv() != nil {
+				continue
+			}
+			curCallee := curId
+			if curId.ParentEdgeKind() == edge.SelectorExpr_Sel {
+				curCallee = curId.Parent()
+			}
+			if curCallee.ParentEdgeKind() != edge.CallExpr_Fun {
+				return fmt.Errorf("function %s is used other than in a call", fn.Name())
+			}
+		}
+	}
+	return nil
+}
+
+// sortedMethods returns the used methods of named, a type of package
+// pkg: first those declared by named, in declaration order, then the
+// promoted ones, ordered by name. The methods of an imported type are
+// ordered by name.
+func sortedMethods(pkg *types.Package, named *types.Named, used map[*types.Func]bool) []*types.Func {
+	index := make(map[*types.Func]int)
+	if named.Obj().Pkg() == pkg {
+		for i := range named.NumMethods() {
+			index[named.Method(i)] = i
+		}
+	}
+	rank := func(m *types.Func) int {
+		if i, ok := index[m]; ok {
+			return i
+		}
+		return named.NumMethods()
+	}
+	var methods []*types.Func
+	for m := range used {
+		methods = append(methods, m)
+	}
+	slices.SortFunc(methods, func(x, y *types.Func) int {
+		return cmp.Or(cmp.Compare(rank(x), rank(y)), cmp.Compare(x.Name(), y.Name()))
+	})
+	return methods
+}
+
+// existingInterface returns the name of an interface type declared in
+// package pkg whose method set is exactly the given methods, or "" if
+// there is none. It consults the method-set index of the package.
+func existingInterface(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, methods []*types.Func) (string, error) {
+	indexes, err := snapshot.MethodSets(ctx, pkg.Metadata().ID)
+	if err != nil {
+		return "", err
+	}
+	var funcs []*types.Func
+	for _, m := range methods {
+		sig := m.Signature()
+		funcs = append(funcs, types.NewFunc(token.NoPos, m.Pkg(), m.Name(),
+			types.NewSignatureType(nil, nil, nil, sig.Params(), sig.Results(), sig.Variadic())))
+	}
+	key, ok := methodsets.KeyOf(types.NewInterfaceType(funcs, nil).Complete())
+	if !ok {
+		return "", nil // no methods
+	}
+	// An identical interface is both a subtype and a supertype.
+	subtypes := indexes[0].Search(key, methodsets.Subtype, nil)
+	for _, res := range indexes[0].Search(key, methodsets.Supertype, nil) {
+		if res.IsInterface && slices.Contains(subtypes, res) {
+			return res.TypeName, nil
+		}
+	}
+	return "", nil
+}
+
+// extractInterface is a [fixer] that declares an interface for the
+// methods of a concrete type (see [canExtractInterface]). For a
+// parameter, it also changes the type of the parameter to the
+// interface, which may be an existing one.
+func extractInterface(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*token.FileSet, *analysis.SuggestedFix, error) {
+	x, err := canExtractInterface(pkg, pgf, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	if x.param == nil {
+		x.methods = typeMethodsUsed(pkg, x.named)
+		if len(x.methods) == 0 {
+			return nil, nil, fmt.Errorf("no methods of %s are used", x.named.Obj().Name())
+		}
+	} else {
+		// Changing the type of the function would break references
+		// to it other than calls, in its own package or in the
+		// packages that may refer to it, including its test variants.
+		if err := checkOnlyCalled(pkg, x.fn); err != nil {
+			return nil, nil, err
+		}
+		rdeps, err := typeCheckReverseDependencies(ctx, snapshot, pgf.URI, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rdep := range rdeps {
+			if err := checkOnlyCalled(rdep, x.fn); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	x.existing, err = existingInterface(ctx, snapshot, pkg, x.methods)
+	if err != nil {
+		return nil, nil, err
+	}
+	if x.existing != "" && x.param == nil {
+		return nil, nil, fmt.Errorf("interface %s already has the methods of %s used in the package", x.existing, x.named.Obj().Name())
+	}
+	info := pkg.TypesInfo()
+	var edits []analysis.TextEdit
+
+	// Qualify references to other packages, adding imports as needed.
+	imported := make(map[string]string) // path => name
+	qual := func(p *types.Package) string {
+		if p == pkg.Types() {
+			return ""
+		}
+		name, ok := imported[p.Path()]
+		if !ok {
+			prefix, importEdits := refactor.AddImport(info, pgf.File, p.Name(), p.Path(), "", pgf.File.Name.Pos())
+			name = strings.TrimSuffix(prefix, ".")
+			imported[p.Path()] = name
+			edits = append(edits, importEdits...)
+		}
+		return name
+	}
+
+	name := x.existing
+	if name == "" {
+		// Declare the interface, after the type declaration or
+		// before the function.
+		var (
+			preferred = x.named.Obj().Name() + "Interface"
+			subject   = x.named.Obj().Name()
+			user      = "package " + pkg.Types().Name()
+		)
+		if x.param != nil {
+			preferred = x.named.Obj().Name()
+			if !x.fn.Exported() {
+				preferred, _ = unexportedName(preferred)
+			}
+			preferred += "Interface"
+			subject = types.TypeString(x.paramVar.Type(), typesinternal.FileQualifier(pgf.File, pkg.Types()))
+			user = x.fn.Name()
+		}
+		name = refactor.FreshName(info.Scopes[pgf.File], pgf.File.Name.Pos(), preferred)
+
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "// %s abstracts the methods of %s used by %s.\n", name, subject, user)
+		fmt.Fprintf(&buf, "type %s interface {\n", name)
+		for _, m := range x.methods {
+			fmt.Fprintf(&buf, "\t%s", m.Name())
+			types.WriteSignature(&buf, m.Signature(), qual)
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}")
+
+		if x.param == nil {
+			edits = append(edits, analysis.TextEdit{
+				Pos:     x.decl.End(),
+				End:     x.decl.End(),
+				NewText: append([]byte("\n\n"), buf.Bytes()...),
+			})
+		} else {
+			pos := x.decl.Pos()
+			if doc := x.decl.(*ast.FuncDecl).Doc; doc != nil {
+				pos = doc.Pos()
+			}
+			buf.WriteString("\n\n")
+			edits = append(edits, analysis.TextEdit{Pos: pos, End: pos, NewText: buf.Bytes()})
+		}
+	}
+
+	if x.param != nil {
+		edits = append(edits, analysis.TextEdit{
+			Pos:     x.param.Type.Pos(),
+			End:     x.param.Type.End(),
+			NewText: []byte(name),
+		})
+
+		// Delete imports used only by the old type of the parameter.
+		var deletes []*ast.ImportSpec
+		for _, spec := range pgf.File.Imports {
+			pkgName := info.PkgNameOf(spec)
+			if pkgName == nil || !slices.Contains(x.pkgNames, pkgName) {
+				continue
+			}
+			if _, ok := imported[pkgName.Imported().Path()]; ok {
+				continue // still needed by the interface
+			}
+			used := false
+			for id, obj := range info.Uses {
+				if obj == pkgName && !(x.param.Type.Pos() <= id.Pos() && id.Pos() < x.param.Type.End()) {
+					used = true
+					break
+				}
+			}
+			if !used {
+				deletes = append(deletes, spec)
+			}
+		}
+		for _, edit := range importDeletesEdits(pgf, deletes) {
+			pos, end, err := pgf.RangePos(edit.Range)
+			if err != nil {
+				return nil, nil, err
+			}
+			edits = append(edits, analysis.TextEdit{Pos: pos, End: end})
+		}
+	}
+
+	// If the file was well formatted, tidy the imports.
+	src := pgf.Src
+	if formatted, err := format.Source(src); err != nil || !bytes.Equal(formatted, src) {
+		return pkg.FileSet(), &analysis.SuggestedFix{TextEdits: edits}, nil
+	}
+	var diffs []diff.Edit
+	for _, edit := range edits {
+		start, end, err := safetoken.Offsets(pgf.Tok, edit.Pos, edit.End)
+		if err != nil {
+			return nil, nil, err
+		}
+		diffs = append(diffs, diff.Edit{Start: start, End: end, New: string(edit.NewText)})
+	}
+	diff.SortEdits(diffs)
+	newSrc, err := diff.ApplyBytes(src, diffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if formatted, err := formatMoved(newSrc); err == nil {
+		newSrc = formatted
+	}
+	return pkg.FileSet(), &analysis.SuggestedFix{TextEdits: diffToTextEdits(pgf.Tok, diff.Bytes(src, newSrc))}, nil
+}
@@ -54,6 +54,7 @@ const (
 	fixExtractVariableAll      = "extract_variable_all"
 	fixExtractFunction         = "extract_function"
 	fixExtractMethod           = "extract_method"
+	fixExtractInterface        = "extract_interface"
 	fixInlineCall              = "inline_call" // keep consistent with go/analysis/passes/inline Diagnostic.Category
 	fixInlineVariable          = "inline_variable"
 	fixInvertIfCondition       = "invert_if_condition"
@@ -98,6 +99,7 @@ func ApplyFix(ctx context.Context, fix string, snapshot *cache.Snapshot, fh file
 		// constructed directly by logic in server/code_action.
 		fixExtractFunction:         singleFile(extractFunction),
 		fixExtractMethod:           singleFile(extractMethod),
+		fixExtractInterface:        extractInterface,
 		fixExtractVariable:         singleFile(extractVariableOne),
 		fixExtractVariableAll:      singleFile(extractVariableAll),
 		fixInlineCall:              inlineCall,
@@ -64,25 +64,7 @@ func Format(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]pr
 	// Apply additional formatting, if any is supported. Currently, the only
 	// supported additional formatter is gofumpt.
 	if snapshot.Options().Gofumpt {
-		// gofumpt can customize formatting based on language version and module
-		// path, if available.
-		//
-		// Try to derive this information, but fall-back on the default behavior.
-		//
-		// TODO: under which circumstances can we fail to find module information?
-		// Can this, for example, result in inconsistent formatting across saves,
-		// due to pending calls to packages.Load?
-		var opts gofumptFormat.Options
-		meta, err := snapshot.NarrowestMetadataForFile(ctx, fh.URI())
-		if err == nil {
-			if mi := meta.Module; mi != nil {
-				if v := mi.GoVersion; v != "" {
-					opts.LangVersion = "go" + v
-				}
-				opts.ModulePath = mi.Path
-			}
-		}
-		b, err := gofumptFormat.Source(buf.Bytes(), opts)
+		b, err := gofumpt(ctx, snapshot, fh, buf.Bytes())
 		if err != nil {
 			return nil, err
 		}
@@ -91,6 +73,43 @@ func Format(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]pr
 	return computeTextEdits(ctx, pgf, formatted)
 }
 
+// gofumpt applies the additional formatting of gofumpt to the
+// gofmt-formatted source src of the file fh.
+func gofumpt(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, src []byte) ([]byte, error) {
+	// gofumpt can customize formatting based on language version and module
+	// path, if available.
+	//
+	// Try to derive this information, but fall-back on the default behavior.
+	//
+	// TODO: under which circumstances can we fail to find module information?
+	// Can this, for example, result in inconsistent formatting across saves,
+	// due to pending calls to packages.Load?
+	var opts gofumptFormat.Options
+	meta, err := snapshot.NarrowestMetadataForFile(ctx, fh.URI())
+	if err == nil {
+		if mi := meta.Module; mi != nil {
+			if v := mi.GoVersion; v != "" {
+				opts.LangVersion = "go" + v
+			}
+			opts.ModulePath = mi.Path
+		}
+	}
+	return gofumptFormat.Source(src, opts)
+}
+
+// formatGoSource formats the source src of the file fh as Format
+// would, applying gofumpt if it is enabled.
+func formatGoSource(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Options().Gofumpt {
+		return gofumpt(ctx, snapshot, fh, formatted)
+	}
+	return formatted, nil
+}
+
 func formatSource(ctx context.Context, fh file.Handle) ([]byte, error) {
 	_, done := event.Start(ctx, "golang.formatSource")
 	defer done()
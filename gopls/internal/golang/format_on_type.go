@@ -0,0 +1,237 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the on-type formatting of Go source
+// (textDocument/onTypeFormatting).
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/event"
+)
+
+// Markers temporarily inserted into the source to find the
+// indentation that gofmt gives to a line. They have the form of
+// directives, whose text gofmt leaves alone.
+const (
+	lineMarker  = "//gopls:ontype"
+	braceMarker = "//gopls:closebrace"
+)
+
+// OnTypeFormat returns the edits that format the Go source affected by
+// the typing of the character ch, which ends just before position pp:
+//
+//   - after a newline, it formats the line just ended, gives the new
+//     line the indentation that gofmt would, and, if the line just ended
+//     opens a block whose closing brace is missing, adds it after the new
+//     line;
+//   - after a closing brace, it formats the statement or declaration
+//     that the brace completes.
+//
+// The edits are those of formatting the entire file, restricted to the
+// affected lines, so they are consistent with Format.
+func OnTypeFormat(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position, ch string) ([]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "golang.OnTypeFormat")
+	defer done()
+
+	switch ch {
+	case "\n":
+		return formatNewline(ctx, snapshot, fh, pp)
+	case "}":
+		return formatClosingBrace(ctx, snapshot, fh, pp)
+	}
+	return nil, nil
+}
+
+// formatNewline returns the edits that format the lines on either side
+// of the newline just before position pp.
+func formatNewline(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position) ([]protocol.TextEdit, error) {
+	src, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	m := protocol.NewMapper(fh.URI(), src)
+	offset, err := m.PositionOffset(pp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the extent of the new line and the line before it,
+	// excluding their line terminators.
+	lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+	if lineStart == 0 {
+		return nil, nil // no preceding line
+	}
+	prevStart := bytes.LastIndexByte(src[:lineStart-1], '\n') + 1
+	prevEnd, eol := lineStart-1, "\n"
+	if prevEnd > prevStart && src[prevEnd-1] == '\r' {
+		prevEnd, eol = prevEnd-1, "\r\n"
+	}
+	lineEnd := len(src)
+	if i := bytes.IndexByte(src[lineStart:], '\n'); i >= 0 {
+		lineEnd = lineStart + i
+	}
+	if lineEnd > lineStart && src[lineEnd-1] == '\r' {
+		lineEnd--
+	}
+	indentEnd := lineStart + len(src[lineStart:lineEnd]) - len(bytes.TrimLeft(src[lineStart:lineEnd], " \t"))
+	blank := indentEnd == lineEnd
+
+	// The new line may be within a multi-line string or comment,
+	// whose text must not be changed.
+	depth, opensBlock, inToken := scanBraces(src, prevStart, indentEnd)
+	if inToken {
+		return nil, nil
+	}
+	needBrace := depth > 0 && opensBlock
+
+	// Format the source with markers on the blank new line and the
+	// added brace, whose indentation could not be determined otherwise.
+	var buf bytes.Buffer
+	buf.Write(src[:indentEnd])
+	if blank {
+		buf.WriteString(lineMarker)
+	}
+	buf.Write(src[indentEnd:lineEnd])
+	if needBrace {
+		buf.WriteString(eol + "} " + braceMarker)
+	}
+	buf.Write(src[lineEnd:])
+	formatted, err := formatGoSource(ctx, snapshot, fh, buf.Bytes())
+	if err != nil {
+		// The file has other syntax errors. Add the missing brace,
+		// at the indentation of the line that opens the block.
+		if needBrace {
+			prev := src[prevStart:prevEnd]
+			indent := prev[:len(prev)-len(bytes.TrimLeft(prev, " \t"))]
+			return protocol.EditsFromDiffEdits(m, []diff.Edit{{Start: lineEnd, End: lineEnd, New: eol + string(indent) + "}"}})
+		}
+		return nil, nil
+	}
+
+	// Restrict the edits of formatting to the two lines.
+	// Those of the new line are valid for src only if it has no marker.
+	var edits []diff.Edit
+	for _, edit := range diff.Bytes(buf.Bytes(), formatted) {
+		if edit.Start >= prevStart && edit.End <= prevEnd ||
+			!blank && edit.Start >= lineStart && edit.End <= lineEnd {
+			edits = append(edits, edit)
+		}
+	}
+	if blank {
+		if indent, text, ok := markedLine(formatted, lineMarker); ok && text == "" && indent != string(src[lineStart:indentEnd]) {
+			edits = append(edits, diff.Edit{Start: lineStart, End: indentEnd, New: indent})
+		}
+	}
+	if needBrace {
+		indent, _, _ := markedLine(formatted, braceMarker)
+		edits = append(edits, diff.Edit{Start: lineEnd, End: lineEnd, New: eol + indent + "}"})
+	}
+	return protocol.EditsFromDiffEdits(m, edits)
+}
+
+// scanBraces scans the Go source src, whose line before offset starts
+// at prevStart. It returns the nesting depth of braces at the end of
+// the source, and reports whether the last token of that line (other
+// than comments) is an opening brace, and whether offset is within a
+// token, such as a multi-line string or comment.
+func scanBraces(src []byte, prevStart, offset int) (depth int, opensBlock, inToken bool) {
+	fset := token.NewFileSet()
+	tok := fset.AddFile("", -1, len(src))
+	var s scanner.Scanner
+	s.Init(tok, src, nil, scanner.ScanComments) // ignore errors
+	for {
+		pos, t, lit := s.Scan()
+		start, err := safetoken.Offset(tok, pos)
+		if err != nil {
+			start = len(src) // EOF
+		}
+		if start < offset && t != token.COMMENT && !(t == token.SEMICOLON && lit == "\n") {
+			opensBlock = t == token.LBRACE && start >= prevStart
+		}
+		switch t {
+		case token.EOF:
+			return depth, opensBlock, inToken
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			depth--
+		case token.STRING, token.CHAR, token.COMMENT:
+			if start < offset && offset < start+len(lit) {
+				inToken = true
+			}
+		}
+	}
+}
+
+// markedLine returns the indentation of the line of the formatted
+// source that contains the marker, and the text between the
+// indentation and the marker.
+func markedLine(formatted []byte, marker string) (indent, text string, ok bool) {
+	i := bytes.Index(formatted, []byte(marker))
+	if i < 0 {
+		return "", "", false
+	}
+	line := formatted[bytes.LastIndexByte(formatted[:i], '\n')+1 : i]
+	rest := bytes.TrimLeft(line, " \t")
+	return string(line[:len(line)-len(rest)]), string(rest), true
+}
+
+// formatClosingBrace returns the edits that format the statement or
+// declaration completed by the closing brace just before position pp.
+func formatClosingBrace(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position) ([]protocol.TextEdit, error) {
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+	if pgf.ParseErr != nil {
+		return nil, nil // can't format a file with syntax errors
+	}
+	pos, err := pgf.PositionPos(pp)
+	if err != nil {
+		return nil, err
+	}
+	if offset, err := safetoken.Offset(pgf.Tok, pos); err != nil || offset == 0 || pgf.Src[offset-1] != '}' {
+		return nil, nil // brace was not typed just before pp
+	}
+
+	// Find the innermost statement or declaration enclosing the brace,
+	// other than the block that it closes.
+	curBrace, ok := pgf.Cursor().FindByPos(pos-1, pos)
+	if !ok {
+		return nil, nil
+	}
+	var node ast.Node
+	for cur := range curBrace.Enclosing() {
+		switch n := cur.Node().(type) {
+		case *ast.BlockStmt:
+			if _, ok := cur.Parent().Node().(*ast.BlockStmt); ok {
+				node = n // a block statement
+			}
+		case ast.Stmt, ast.Decl:
+			node = n
+		}
+		if node != nil {
+			break
+		}
+	}
+	if node == nil {
+		return nil, nil
+	}
+
+	return formatRegions(ctx, snapshot, fh, pgf, []astutil.Range{astutil.NodeRange(node)})
+}
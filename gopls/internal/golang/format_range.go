@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the formatting of parts of a Go file
+// (textDocument/rangeFormatting and textDocument/rangesFormatting).
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/event"
+)
+
+// FormatRanges formats the parts of a file covered by the given ranges.
+//
+// For each range, it formats the smallest statement or declaration
+// that encloses it or, if the range spans several statements of a
+// block (or declarations of the file), those statements. The edits are
+// those of formatting the entire file, restricted to the lines of these
+// statements, so that the rest of the file (which may not be
+// gofmt-clean) is left unchanged.
+func FormatRanges(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rngs []protocol.Range) ([]protocol.TextEdit, error) {
+	ctx, done := event.Start(ctx, "golang.FormatRanges")
+	defer done()
+
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+	if pgf.ParseErr != nil {
+		// A file with syntax errors can't be formatted;
+		// report the error as Format does.
+		_, err := formatSource(ctx, fh)
+		return nil, err
+	}
+
+	var regions []astutil.Range
+	for _, rng := range rngs {
+		start, end, err := pgf.RangePos(rng)
+		if err != nil {
+			return nil, err
+		}
+		cur, _, _, _ := astutil.Select(pgf.Cursor(), start, end) // can't fail: pgf contains range
+		regions = append(regions, enclosingStmts(cur, start, end)...)
+	}
+	return formatRegions(ctx, snapshot, fh, pgf, regions)
+}
+
+// enclosingStmts returns the extents of the smallest statement or
+// declaration that encloses the cursor, or, if the cursor is a list of
+// statements (or the file), of its elements that intersect [start, end).
+func enclosingStmts(cur inspector.Cursor, start, end token.Pos) []astutil.Range {
+	for cur := range cur.Enclosing() {
+		var list []ast.Node
+		switch n := cur.Node().(type) {
+		case *ast.File:
+			for _, decl := range n.Decls {
+				list = append(list, decl)
+			}
+		case *ast.BlockStmt:
+			if _, ok := cur.Parent().Node().(*ast.BlockStmt); ok {
+				return []astutil.Range{astutil.NodeRange(n)} // a block statement
+			}
+			for _, stmt := range n.List {
+				list = append(list, stmt)
+			}
+		case *ast.CaseClause:
+			for _, stmt := range n.Body {
+				list = append(list, stmt)
+			}
+		case *ast.CommClause:
+			for _, stmt := range n.Body {
+				list = append(list, stmt)
+			}
+		case ast.Stmt, ast.Decl:
+			return []astutil.Range{astutil.NodeRange(n)}
+		default:
+			continue
+		}
+		var regions []astutil.Range
+		for _, n := range list {
+			if n.Pos() < end && start < n.End() {
+				regions = append(regions, astutil.NodeRange(n))
+			}
+		}
+		return regions
+	}
+	return nil
+}
+
+// formatRegions returns the edits that format the given regions of the
+// file: those of formatting the entire file that lie within the lines
+// spanned by one of the regions.
+func formatRegions(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pgf *parsego.File, regions []astutil.Range) ([]protocol.TextEdit, error) {
+	if len(regions) == 0 {
+		return nil, nil
+	}
+
+	// Extend each region to whole lines, excluding the final line
+	// terminator, so that indentation and trailing comments are formatted.
+	type span struct{ start, end int }
+	var spans []span
+	for _, r := range regions {
+		start, end, err := safetoken.Offsets(pgf.Tok, r.Pos(), r.End())
+		if err != nil {
+			return nil, err
+		}
+		start = bytes.LastIndexByte(pgf.Src[:start], '\n') + 1
+		if i := bytes.IndexByte(pgf.Src[end:], '\n'); i >= 0 {
+			end += i
+		} else {
+			end = len(pgf.Src)
+		}
+		spans = append(spans, span{start, end})
+	}
+
+	formatted, err := formatGoSource(ctx, snapshot, fh, pgf.Src)
+	if err != nil {
+		return nil, err
+	}
+	var edits []diff.Edit
+	for _, edit := range diff.Bytes(pgf.Src, formatted) {
+		for _, s := range spans {
+			if edit.Start >= s.start && edit.End <= s.end {
+				edits = append(edits, edit)
+				break
+			}
+		}
+	}
+	return protocol.EditsFromDiffEdits(pgf.Mapper, edits)
+}
@@ -0,0 +1,919 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "Convert to functional options" refactoring,
+// which replaces parameters of a function by a variadic list of
+// options, in the functional options pattern, and its inverse,
+// "Convert from functional options".
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	goastutil "golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/bug"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/internal/util/tokeninternal"
+	"golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// selectOptionParams returns the parameters of a function declaration
+// spanned by the selection [start, end), for conversion to functional
+// options. The function may not already be variadic.
+func selectOptionParams(pgf *parsego.File, start, end token.Pos) (*paramSelection, error) {
+	sel, err := selectParams(pgf, start, end, 1)
+	if err != nil {
+		return nil, err
+	}
+	if params := sel.decl.Type.Params.List; is[*ast.Ellipsis](params[len(params)-1].Type) {
+		return nil, fmt.Errorf("function is already variadic")
+	}
+	return sel, nil
+}
+
+// ConvertToFunctionalOptions computes a refactoring that replaces the
+// consecutive parameters of the function declaration selected by rng
+// with a final variadic parameter of a new option type, in the
+// functional options pattern. It declares the option type, a struct
+// type holding the selected parameters, and a constructor of an option
+// for each of them. All calls to the function are rewritten to pass
+// the options.
+//
+// For example, given the selection of addr and timeout in
+//
+//	func NewServer(name string, addr string, timeout time.Duration) *Server {
+//		return &Server{name, addr, timeout}
+//	}
+//
+// the declaration becomes
+//
+//	// ServerOption configures a call to NewServer.
+//	type ServerOption func(*serverOptions)
+//
+//	// serverOptions holds the optional parameters of NewServer.
+//	type serverOptions struct {
+//		addr    string
+//		timeout time.Duration
+//	}
+//
+//	// WithAddr sets the addr parameter of NewServer.
+//	func WithAddr(addr string) ServerOption {
+//		return func(o *serverOptions) {
+//			o.addr = addr
+//		}
+//	}
+//
+//	// (and likewise WithTimeout)
+//
+//	func NewServer(name string, opts ...ServerOption) *Server {
+//		var options serverOptions
+//		for _, opt := range opts {
+//			opt(&options)
+//		}
+//		addr, timeout := options.addr, options.timeout
+//		return &Server{name, addr, timeout}
+//	}
+//
+// and a call NewServer("x", ":80", 0) becomes
+// NewServer("x", WithAddr(":80"), WithTimeout(0)).
+//
+// The option type and constructors are exported if the function is.
+//
+// Like [ChangeSignature], this rewrite works by inlining calls to a
+// wrapper of the new declaration.
+func ConvertToFunctionalOptions(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range) ([]protocol.DocumentChange, error) {
+	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
+		var sample string
+		if len(perrors) > 0 {
+			sample = perrors[0].Error()
+		} else {
+			sample = terrors[0].Error()
+		}
+		return nil, fmt.Errorf("can't change signatures for packages with parse or type errors: (e.g. %s)", sample)
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := selectOptionParams(pgf, start, end)
+	if err != nil {
+		return nil, err
+	}
+	decl := sel.decl
+	fset := tokeninternal.FileSetFor(pgf.Tok)
+
+	// Choose the names of the new package-level declarations, which
+	// must not conflict with existing ones, or each other.
+	taken := make(map[string]bool)
+	fresh := func(name string) string {
+		scope := pkg.TypesInfo().Scopes[pgf.File]
+		for i := 0; ; i++ {
+			candidate := name
+			if i > 0 {
+				candidate = fmt.Sprintf("%s%d", name, i)
+			}
+			if _, obj := scope.LookupParent(candidate, token.NoPos); obj == nil && !taken[candidate] {
+				taken[candidate] = true
+				return candidate
+			}
+		}
+	}
+	exported := decl.Name.IsExported()
+	base := decl.Name.Name
+	for _, prefix := range []string{"New", "new"} {
+		if rest, ok := strings.CutPrefix(base, prefix); ok && rest != "" {
+			base = rest
+			break
+		}
+	}
+	var (
+		exportedBase, _   = exportedName(base)
+		unexportedBase, _ = unexportedName(base)
+		optionType        = fresh(cond(exported, exportedBase, unexportedBase) + "Option")
+		structType        = fresh(unexportedBase + "Options")
+		withFuncs         = make([]string, len(sel.names))
+		fields            = make([]string, len(sel.names))
+		paramTypes        = make([]string, len(sel.names))
+	)
+	for i, id := range sel.names {
+		fields[i] = id.Name
+		paramTypes[i] = FormatNode(fset, sel.types[i])
+		suffix, _ := exportedName(id.Name)
+		withFuncs[i] = fresh(cond(exported, "With", "with") + suffix)
+	}
+
+	// Choose the names of the new local variables, which must not
+	// conflict with any name in the declaration.
+	used := make(map[string]bool)
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	freshLocal := func(name string) string {
+		candidate := name
+		for i := 0; used[candidate]; i++ {
+			candidate = fmt.Sprintf("%s%d", name, i)
+		}
+		used[candidate] = true
+		return candidate
+	}
+	var (
+		optsName    = freshLocal("opts")
+		optionsName = freshLocal("options")
+		optName     = freshLocal("opt")
+	)
+	// The parameter of each option function must not shadow its
+	// constructor's parameter.
+	recvName := "o"
+	for i := 0; isSelected(sel, recvName); i++ {
+		recvName = fmt.Sprintf("o%d", i)
+	}
+
+	var decls strings.Builder
+	fmt.Fprintf(&decls, "// %s configures a call to %s.\n", optionType, decl.Name.Name)
+	fmt.Fprintf(&decls, "type %s func(*%s)\n\n", optionType, structType)
+	fmt.Fprintf(&decls, "// %s holds the optional parameters of %s.\n", structType, decl.Name.Name)
+	fmt.Fprintf(&decls, "type %s struct {\n", structType)
+	for i, field := range fields {
+		fmt.Fprintf(&decls, "\t%s %s\n", field, paramTypes[i])
+	}
+	decls.WriteString("}\n")
+	for i, field := range fields {
+		fmt.Fprintf(&decls, "\n// %s sets the %s parameter of %s.\n", withFuncs[i], field, decl.Name.Name)
+		fmt.Fprintf(&decls, "func %s(%s %s) %s {\n", withFuncs[i], field, paramTypes[i], optionType)
+		fmt.Fprintf(&decls, "\treturn func(%s *%s) {\n", recvName, structType)
+		fmt.Fprintf(&decls, "\t\t%s.%s = %s\n", recvName, field, field)
+		decls.WriteString("\t}\n}\n")
+	}
+
+	// The prologue of the new declaration collects the options, and
+	// declares the selected parameters that it uses as local variables.
+	var prologue []ast.Stmt
+	{
+		prologue = append(prologue,
+			&ast.DeclStmt{Decl: &ast.GenDecl{
+				Tok: token.VAR,
+				Specs: []ast.Spec{&ast.ValueSpec{
+					Names: []*ast.Ident{ast.NewIdent(optionsName)},
+					Type:  ast.NewIdent(structType),
+				}},
+			}},
+			&ast.RangeStmt{
+				Key:   ast.NewIdent("_"),
+				Value: ast.NewIdent(optName),
+				Tok:   token.DEFINE,
+				X:     ast.NewIdent(optsName),
+				Body: &ast.BlockStmt{List: []ast.Stmt{
+					&ast.ExprStmt{X: &ast.CallExpr{
+						Fun:  ast.NewIdent(optName),
+						Args: []ast.Expr{&ast.UnaryExpr{Op: token.AND, X: ast.NewIdent(optionsName)}},
+					}},
+				}},
+			})
+		uses := make(map[types.Object]bool)
+		for id, obj := range pkg.TypesInfo().Uses {
+			if decl.Body.Pos() <= id.Pos() && id.Pos() < decl.Body.End() {
+				uses[obj] = true
+			}
+		}
+		assign := &ast.AssignStmt{Tok: token.DEFINE}
+		for i, id := range sel.names {
+			if uses[pkg.TypesInfo().Defs[id]] {
+				assign.Lhs = append(assign.Lhs, ast.NewIdent(id.Name))
+				assign.Rhs = append(assign.Rhs, &ast.SelectorExpr{
+					X:   ast.NewIdent(optionsName),
+					Sel: ast.NewIdent(fields[i]),
+				})
+			}
+		}
+		if len(assign.Lhs) > 0 {
+			prologue = append(prologue, assign)
+		}
+	}
+
+	// Step 1: create the new declaration, in which the selected
+	// parameters are replaced by the variadic options.
+	newDecl := astutil.CloneNode(decl)
+	newDecl.Type.Params = optionParams(decl.Type.Params, sel, optsName, optionType)
+	newDecl.Body.List = append(prologue, newDecl.Body.List...)
+
+	// Step 2: build a wrapper function calling the new declaration with
+	// an option for each selected parameter.
+	params, names, variadic := delegatingParams(decl, func(int) bool { return true })
+	var args []ast.Expr
+	for _, name := range names[:sel.first] {
+		args = append(args, ast.NewIdent(name))
+	}
+	for _, name := range names[sel.last:] {
+		args = append(args, ast.NewIdent(name))
+	}
+	for i, with := range withFuncs {
+		args = append(args, &ast.CallExpr{
+			Fun:  ast.NewIdent(with),
+			Args: []ast.Expr{ast.NewIdent(names[sel.first+i])},
+		})
+	}
+
+	// Step 3: rewrite all referring calls, by swapping in the wrapper and
+	// inlining all.
+	newContent, err := rewriteCalls(ctx, signatureRewrite{
+		snapshot: snapshot,
+		pkg:      pkg,
+		pgf:      pgf,
+		origDecl: decl,
+		newDecl:  newDecl,
+		params:   params,
+		callArgs: args,
+		variadic: variadic,
+		decls:    decls.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Finally, rewrite the original declaration: its signature, and
+	// the prologue of its body, and declare the new declarations
+	// before it.
+	{
+		idx := findDecl(pgf.File, decl)
+		if idx < 0 {
+			return nil, bug.Errorf("didn't find original decl")
+		}
+		src, ok := newContent[pgf.URI]
+		if !ok {
+			src = pgf.Src
+		}
+		src, err := rewriteSignature(fset, idx, src, newDecl)
+		if err != nil {
+			return nil, err
+		}
+		var text strings.Builder
+		for _, stmt := range prologue {
+			text.WriteString("\n" + FormatNode(token.NewFileSet(), stmt))
+		}
+		src, err = insertPrologue(src, decl.Name.Name, idx, decls.String(), text.String())
+		if err != nil {
+			return nil, err
+		}
+		if formatted, err := format.Source(src); err == nil {
+			src = formatted
+		}
+		newContent[pgf.URI] = src
+	}
+
+	return documentChanges(ctx, snapshot, newContent)
+}
+
+// isSelected reports whether name is the name of a selected parameter.
+func isSelected(sel *paramSelection, name string) bool {
+	for _, id := range sel.names {
+		if id.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// optionParams returns a copy of the parameter list params in which
+// the selected parameters are removed, and a final variadic parameter
+// of the named option type is added.
+func optionParams(params *ast.FieldList, sel *paramSelection, name, optionType string) *ast.FieldList {
+	list := &ast.FieldList{}
+	i := 0 // index of (flattened) parameter
+	for _, field := range params.List {
+		if len(field.Names) == 0 {
+			list.List = append(list.List, astutil.CloneNode(field))
+			i++
+			continue
+		}
+		var names []*ast.Ident
+		for _, id := range field.Names {
+			if i < sel.first || i >= sel.last {
+				names = append(names, ast.NewIdent(id.Name))
+			}
+			i++
+		}
+		if len(names) > 0 {
+			list.List = append(list.List, &ast.Field{Names: names, Type: astutil.CloneNode(field.Type)})
+		}
+	}
+	list.List = append(list.List, &ast.Field{
+		Names: []*ast.Ident{ast.NewIdent(name)},
+		Type:  &ast.Ellipsis{Elt: ast.NewIdent(optionType)},
+	})
+	return list
+}
+
+// insertPrologue inserts the statements text at the start of the body
+// of the idx'th declaration, the function of the specified name, of
+// the content src of the declaring file, and the declarations decls
+// before it.
+func insertPrologue(src []byte, name string, idx int, decls, text string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, bug.Errorf("re-parsing declaring file failed: %v", err)
+	}
+	decl, _ := file.Decls[idx].(*ast.FuncDecl)
+	if decl == nil || decl.Name.Name != name {
+		return nil, bug.Errorf("inlining affected declaration order: found %v, not func %s", decl, name)
+	}
+	tok := fset.File(file.FileStart)
+	pos := decl.Pos()
+	if decl.Doc != nil {
+		pos = decl.Doc.Pos()
+	}
+	declStart, err := safetoken.Offset(tok, pos)
+	if err != nil {
+		return nil, err
+	}
+	// Keep a comment that follows the opening brace on its line.
+	bodyPos := decl.Body.Lbrace + 1
+	for _, c := range file.Comments {
+		if c.Pos() >= bodyPos && tok.Line(c.Pos()) == tok.Line(decl.Body.Lbrace) {
+			bodyPos = c.End()
+		}
+	}
+	bodyStart, err := safetoken.Offset(tok, bodyPos)
+	if err != nil {
+		return nil, err
+	}
+	return diff.ApplyBytes(src, []diff.Edit{
+		{Start: declStart, End: declStart, New: decls + "\n"},
+		{Start: bodyStart, End: bodyStart, New: text},
+	})
+}
+
+// optionsDecl describes a function declaration whose final parameter
+// is a variadic list of functional options, in the form produced by
+// [ConvertToFunctionalOptions].
+type optionsDecl struct {
+	decl         *ast.FuncDecl
+	nparams      int            // number of (flattened) parameters, including the options
+	prologue     []ast.Stmt     // statements that apply the options
+	fields       []*types.Var   // fields of the options struct type
+	names        []string       // names of the parameters that replace the options
+	constructors map[string]int // maps the name of each option constructor to the index of its field
+}
+
+// matchOptionsDecl returns the function declaration whose final
+// variadic parameter of functional options is spanned by the selection
+// [start, end), for conversion back to ordinary parameters.
+//
+// The options must be of the form produced by
+// [ConvertToFunctionalOptions]: the option type is a function of a
+// pointer to a struct type declared in the same package, and the
+// function body begins by applying the options to a variable of that
+// type, and then declaring local variables for the fields it uses.
+// The constructors of options are the package-level functions that
+// return an option setting a single field to their argument.
+func matchOptionsDecl(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) (*optionsDecl, error) {
+	path, _ := goastutil.PathEnclosingInterval(pgf.File, start, end)
+	var decl *ast.FuncDecl
+	for _, n := range path {
+		if n, ok := n.(*ast.FuncDecl); ok {
+			decl = n
+			break
+		}
+	}
+	if decl == nil || decl.Type.Params.NumFields() == 0 {
+		return nil, fmt.Errorf("selection is not within a parameter list")
+	}
+	last := decl.Type.Params.List[len(decl.Type.Params.List)-1]
+	if !(last.Pos() <= start && end <= last.End()) || !is[*ast.Ellipsis](last.Type) || len(last.Names) != 1 {
+		return nil, fmt.Errorf("selection is not within a final variadic parameter")
+	}
+	if decl.Body == nil {
+		return nil, fmt.Errorf("function has no body")
+	}
+	if decl.Type.TypeParams != nil || decl.Recv != nil && isGenericRecv(decl.Recv.List[0].Type) {
+		return nil, fmt.Errorf("generic functions are not supported")
+	}
+
+	// Check the types of the option and of the struct it configures.
+	info := pkg.TypesInfo()
+	fn, _ := info.Defs[decl.Name].(*types.Func)
+	optsVar, _ := info.Defs[last.Names[0]].(*types.Var)
+	if fn == nil || optsVar == nil {
+		return nil, bug.Errorf("missing types for function %s", decl.Name.Name)
+	}
+	errNotOptions := fmt.Errorf("final parameter is not a list of functional options")
+	slice, _ := optsVar.Type().(*types.Slice)
+	if slice == nil {
+		return nil, errNotOptions
+	}
+	optionType, _ := types.Unalias(slice.Elem()).(*types.Named)
+	if optionType == nil || optionType.Obj().Pkg() != pkg.Types() || optionType.TypeParams() != nil {
+		return nil, errNotOptions
+	}
+	sig, _ := optionType.Underlying().(*types.Signature)
+	if sig == nil || sig.Params().Len() != 1 || sig.Results().Len() != 0 || sig.Variadic() {
+		return nil, errNotOptions
+	}
+	ptr, _ := sig.Params().At(0).Type().(*types.Pointer)
+	if ptr == nil {
+		return nil, errNotOptions
+	}
+	structType, _ := types.Unalias(ptr.Elem()).(*types.Named)
+	if structType == nil || structType.Obj().Pkg() != pkg.Types() || structType.TypeParams() != nil {
+		return nil, errNotOptions
+	}
+	st, _ := structType.Underlying().(*types.Struct)
+	if st == nil || st.NumFields() == 0 {
+		return nil, errNotOptions
+	}
+	od := &optionsDecl{
+		decl:         decl,
+		nparams:      fn.Signature().Params().Len(),
+		constructors: make(map[string]int),
+	}
+	for field := range st.Fields() {
+		if field.Embedded() || field.Name() == "_" {
+			return nil, fmt.Errorf("options type %s has an embedded or blank field", structType.Obj().Name())
+		}
+		od.fields = append(od.fields, field)
+	}
+
+	// isUse reports whether e is a reference to obj.
+	isUse := func(e ast.Expr, obj types.Object) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && obj != nil && info.Uses[id] == obj
+	}
+
+	// Match the prologue:
+	//
+	//	var options S
+	//	for _, opt := range opts {
+	//		opt(&options)
+	//	}
+	//	x, y := options.x, options.y // optional
+	errNoPrologue := fmt.Errorf("function body does not begin by applying the options")
+	stmts := decl.Body.List
+	if len(stmts) < 2 {
+		return nil, errNoPrologue
+	}
+	var optionsVar *types.Var
+	if declStmt, ok := stmts[0].(*ast.DeclStmt); ok {
+		if gen := declStmt.Decl.(*ast.GenDecl); gen.Tok == token.VAR && len(gen.Specs) == 1 {
+			if spec := gen.Specs[0].(*ast.ValueSpec); len(spec.Names) == 1 && len(spec.Values) == 0 {
+				if v, ok := info.Defs[spec.Names[0]].(*types.Var); ok && types.Identical(v.Type(), structType) {
+					optionsVar = v
+				}
+			}
+		}
+	}
+	loop, _ := stmts[1].(*ast.RangeStmt)
+	if optionsVar == nil || loop == nil || loop.Tok != token.DEFINE {
+		return nil, errNoPrologue
+	}
+	key, _ := loop.Key.(*ast.Ident)
+	value, _ := loop.Value.(*ast.Ident)
+	if key == nil || key.Name != "_" || value == nil || !isUse(loop.X, optsVar) || len(loop.Body.List) != 1 {
+		return nil, errNoPrologue
+	}
+	optVar := info.Defs[value]
+	if stmt, ok := loop.Body.List[0].(*ast.ExprStmt); !ok {
+		return nil, errNoPrologue
+	} else if call, ok := stmt.X.(*ast.CallExpr); !ok || !isUse(call.Fun, optVar) || len(call.Args) != 1 {
+		return nil, errNoPrologue
+	} else if addr, ok := call.Args[0].(*ast.UnaryExpr); !ok || addr.Op != token.AND || !isUse(addr.X, optionsVar) {
+		return nil, errNoPrologue
+	}
+	od.prologue = stmts[:2]
+	locals := make(map[*types.Var]string) // maps each field to the local variable that holds it
+	if len(stmts) > 2 {
+		if assign, ok := stmts[2].(*ast.AssignStmt); ok && assign.Tok == token.DEFINE && len(assign.Lhs) == len(assign.Rhs) {
+			vars := make(map[*types.Var]string)
+			for i, lhs := range assign.Lhs {
+				id, _ := lhs.(*ast.Ident)
+				sel, _ := assign.Rhs[i].(*ast.SelectorExpr)
+				if id == nil || info.Defs[id] == nil || sel == nil || !isUse(sel.X, optionsVar) {
+					vars = nil
+					break
+				}
+				field, _ := info.Uses[sel.Sel].(*types.Var)
+				if _, dup := vars[field]; dup || field == nil {
+					vars = nil
+					break
+				}
+				vars[field] = id.Name
+			}
+			if vars != nil {
+				od.prologue = stmts[:3]
+				locals = vars
+			}
+		}
+	}
+	prologueEnd := od.prologue[len(od.prologue)-1].End()
+	for id, obj := range info.Uses {
+		if (obj == optsVar || obj == optionsVar) && prologueEnd <= id.Pos() && id.Pos() < decl.Body.End() {
+			return nil, fmt.Errorf("options are used after the prologue that applies them")
+		}
+	}
+
+	// Choose the names of the new parameters. A field that was not
+	// held in a local variable becomes a parameter of the same name,
+	// unless that would conflict with a name used by the declaration.
+	used := make(map[string]bool)
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	for _, field := range od.fields {
+		name, ok := locals[field]
+		if !ok {
+			name = cond(used[field.Name()], "_", field.Name())
+		}
+		od.names = append(od.names, name)
+	}
+
+	// Find the constructors of options.
+	for _, f := range pkg.CompiledGoFiles() {
+		for _, d := range f.File.Decls {
+			if d, ok := d.(*ast.FuncDecl); ok {
+				if i := optionConstructorField(info, d, optionType, od.fields); i >= 0 {
+					od.constructors[d.Name.Name] = i
+				}
+			}
+		}
+	}
+	return od, nil
+}
+
+// optionConstructorField returns the index of the field among fields
+// set by the options that decl constructs, or -1 if decl is not a
+// constructor of options of the specified type, of the form
+//
+//	func WithX(x T) Option {
+//		return func(o *S) {
+//			o.x = x
+//		}
+//	}
+func optionConstructorField(info *types.Info, decl *ast.FuncDecl, optionType types.Type, fields []*types.Var) int {
+	if decl.Recv != nil || decl.Type.TypeParams != nil || decl.Body == nil || len(decl.Body.List) != 1 {
+		return -1
+	}
+	fn, _ := info.Defs[decl.Name].(*types.Func)
+	if fn == nil {
+		return -1
+	}
+	sig := fn.Signature()
+	if sig.Params().Len() != 1 || sig.Variadic() || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), optionType) {
+		return -1
+	}
+	param := sig.Params().At(0)
+	ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return -1
+	}
+	lit, ok := ret.Results[0].(*ast.FuncLit)
+	if !ok || len(lit.Type.Params.List) != 1 || len(lit.Type.Params.List[0].Names) != 1 || len(lit.Body.List) != 1 {
+		return -1
+	}
+	recv := info.Defs[lit.Type.Params.List[0].Names[0]]
+	assign, ok := lit.Body.List[0].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return -1
+	}
+	sel, ok := assign.Lhs[0].(*ast.SelectorExpr)
+	if !ok {
+		return -1
+	}
+	if x, ok := sel.X.(*ast.Ident); !ok || recv == nil || info.Uses[x] != recv {
+		return -1
+	}
+	if rhs, ok := assign.Rhs[0].(*ast.Ident); !ok || info.Uses[rhs] != param {
+		return -1
+	}
+	for i, field := range fields {
+		if info.Uses[sel.Sel] == field && types.Identical(param.Type(), field.Type()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ConvertFromFunctionalOptions computes a refactoring that replaces
+// the final variadic parameter of functional options of the function
+// declaration selected by rng with a parameter for each field of the
+// struct type that the options configure. It is the inverse of
+// [ConvertToFunctionalOptions], and requires the options to be of the
+// form that it produces.
+//
+// All calls to the function are rewritten to pass the argument of each
+// option as the corresponding parameter, or its zero value if the
+// option is absent. Each call must pass only calls to constructors of
+// options, and set each field at most once. The declarations of the
+// option type, the struct type, and the constructors are left in
+// place, as they may have other uses.
+func ConvertFromFunctionalOptions(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range) ([]protocol.DocumentChange, error) {
+	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
+		var sample string
+		if len(perrors) > 0 {
+			sample = perrors[0].Error()
+		} else {
+			sample = terrors[0].Error()
+		}
+		return nil, fmt.Errorf("can't change signatures for packages with parse or type errors: (e.g. %s)", sample)
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	od, err := matchOptionsDecl(pkg, pgf, start, end)
+	if err != nil {
+		return nil, err
+	}
+	decl := od.decl
+
+	var (
+		srcs  = make(map[protocol.DocumentURI][]byte)
+		edits = make(map[protocol.DocumentURI][]diff.Edit)
+	)
+	addEdit := func(pgf *parsego.File, start, end token.Pos, text string) error {
+		startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, start, end)
+		if err != nil {
+			return err
+		}
+		srcs[pgf.URI] = pgf.Src
+		edits[pgf.URI] = append(edits[pgf.URI], diff.Edit{Start: startOffset, End: endOffset, New: text})
+		return nil
+	}
+
+	// Replace the options parameter by the new parameters.
+	{
+		qual, missing := importQualifier(pgf.File, pkg.Types())
+		var params []string
+		for i, field := range od.fields {
+			params = append(params, od.names[i]+" "+types.TypeString(field.Type(), qual))
+		}
+		if missing() {
+			return nil, fmt.Errorf("the types of the options are not accessible from %s", pgf.URI.Base())
+		}
+		last := decl.Type.Params.List[len(decl.Type.Params.List)-1]
+		if err := addEdit(pgf, last.Pos(), last.End(), strings.Join(params, ", ")); err != nil {
+			return nil, err
+		}
+	}
+
+	// Delete the prologue, and the remainder of its final line.
+	{
+		start, end, err := safetoken.Offsets(pgf.Tok, od.prologue[0].Pos(), od.prologue[len(od.prologue)-1].End())
+		if err != nil {
+			return nil, err
+		}
+		for start > 0 && (pgf.Src[start-1] == ' ' || pgf.Src[start-1] == '\t') {
+			start--
+		}
+		if end < len(pgf.Src) && pgf.Src[end] == '\n' {
+			end++
+		}
+		srcs[pgf.URI] = pgf.Src
+		edits[pgf.URI] = append(edits[pgf.URI], diff.Edit{Start: start, End: end})
+	}
+
+	// Rewrite the calls.
+	var refs []protocol.Location
+	{
+		funcRng, err := pgf.Mapper.PosRange(pgf.Tok, decl.Name.NamePos, decl.Name.NamePos)
+		if err != nil {
+			return nil, err
+		}
+		fh, err := snapshot.ReadFile(ctx, pgf.URI)
+		if err != nil {
+			return nil, err
+		}
+		refs, err = References(ctx, snapshot, fh, funcRng, false)
+		if err != nil {
+			return nil, fmt.Errorf("finding references to rewrite: %v", err)
+		}
+	}
+	for _, ref := range refs {
+		refPkg, refPgf, err := NarrowestPackageForFile(ctx, snapshot, ref.URI)
+		if err != nil {
+			return nil, err
+		}
+		if !snapshot.IsWorkspacePackage(refPkg.Metadata().ID) {
+			return nil, fmt.Errorf("cannot update call outside the workspace, at %s", ref.URI.Path())
+		}
+		text, start, end, err := rewriteOptionsCall(od, pkg, refPkg, refPgf, ref)
+		if err != nil {
+			return nil, err
+		}
+		if err := addEdit(refPgf, start, end, text); err != nil {
+			return nil, err
+		}
+	}
+
+	newContent := make(map[protocol.DocumentURI][]byte)
+	for uri, src := range srcs {
+		src, err := diff.ApplyBytes(src, edits[uri])
+		if err != nil {
+			// e.g. overlapping edits due to nested calls
+			return nil, fmt.Errorf("cannot rewrite calls in %s: %v", uri.Base(), err)
+		}
+		if uri == pgf.URI {
+			if formatted, err := format.Source(src); err == nil {
+				src = formatted
+			}
+		}
+		newContent[uri] = src
+	}
+	return documentChanges(ctx, snapshot, newContent)
+}
+
+// rewriteOptionsCall returns the replacement text for the options
+// passed by the call to the function of od at ref, and the range of
+// the call it replaces.
+func rewriteOptionsCall(od *optionsDecl, pkg, refPkg *cache.Package, refPgf *parsego.File, ref protocol.Location) (string, token.Pos, token.Pos, error) {
+	start, end, err := refPgf.RangePos(ref.Range)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	info := refPkg.TypesInfo()
+	posn := safetoken.StartPosition(refPkg.FileSet(), start)
+
+	// Find the call.
+	path, _ := goastutil.PathEnclosingInterval(refPgf.File, start, end)
+	id, _ := path[0].(*ast.Ident)
+	if id == nil || len(path) < 2 {
+		return "", 0, 0, bug.Errorf("reference is not an identifier")
+	}
+	var fun ast.Node = id
+	if sel, ok := path[1].(*ast.SelectorExpr); ok && sel.Sel == id {
+		if s := info.Selections[sel]; s != nil && s.Kind() == types.MethodExpr {
+			return "", 0, 0, fmt.Errorf("cannot rewrite method expression at %s", posn)
+		}
+		fun, path = sel, path[1:]
+	}
+	call, _ := path[1].(*ast.CallExpr)
+	if call == nil || call.Fun != fun {
+		return "", 0, 0, fmt.Errorf("cannot rewrite non-call reference at %s", posn)
+	}
+	if call.Ellipsis.IsValid() || len(call.Args) < od.nparams-1 {
+		return "", 0, 0, fmt.Errorf("call at %s does not pass a list of options", posn)
+	}
+
+	// Find the argument of each option.
+	var (
+		options = call.Args[od.nparams-1:]
+		args    = make([]ast.Expr, len(od.fields)) // argument of each option, if any
+		order   []int                              // indices of the fields, in order of options
+	)
+	for _, opt := range options {
+		i := -1
+		if c, ok := ast.Unparen(opt).(*ast.CallExpr); ok && len(c.Args) == 1 && !c.Ellipsis.IsValid() {
+			if fn, ok := typeutil.Callee(info, c).(*types.Func); ok && fn.Pkg() != nil && fn.Pkg().Path() == pkg.Types().Path() {
+				if index, ok := od.constructors[fn.Name()]; ok {
+					i = index
+					if args[i] != nil {
+						return "", 0, 0, fmt.Errorf("call at %s sets the %s option more than once", posn, od.fields[i].Name())
+					}
+					args[i] = c.Args[0]
+				}
+			}
+		}
+		if i < 0 {
+			return "", 0, 0, fmt.Errorf("call at %s passes an option other than a call to an option constructor", posn)
+		}
+		order = append(order, i)
+	}
+	// The arguments are now evaluated in the order of the fields.
+	if !slices.IsSorted(order) {
+		for _, arg := range args {
+			if arg != nil && !typesinternal.NoEffects(info, arg) {
+				return "", 0, 0, fmt.Errorf("call at %s passes options out of order with side effects", posn)
+			}
+		}
+	}
+
+	// Format the new arguments.
+	var (
+		qual, missing = importQualifier(refPgf.File, refPkg.Types())
+		texts         []string
+	)
+	for i, arg := range args {
+		if arg != nil {
+			start, end, err := safetoken.Offsets(refPgf.Tok, arg.Pos(), arg.End())
+			if err != nil {
+				return "", 0, 0, err
+			}
+			texts = append(texts, string(refPgf.Src[start:end]))
+			continue
+		}
+		zero, ok := typesinternal.ZeroExpr(od.fields[i].Type(), qual)
+		ast.Inspect(zero, func(n ast.Node) bool {
+			if sel, isSel := n.(*ast.SelectorExpr); isSel && !sel.Sel.IsExported() {
+				ok = false // inaccessible type
+			}
+			return ok
+		})
+		if !ok || missing() {
+			return "", 0, 0, fmt.Errorf("cannot express the zero value of the %s option at %s", od.fields[i].Name(), posn)
+		}
+		texts = append(texts, FormatNode(token.NewFileSet(), zero))
+	}
+	text := strings.Join(texts, ", ")
+	switch {
+	case len(options) > 0:
+		return text, options[0].Pos(), options[len(options)-1].End(), nil
+	case od.nparams > 1:
+		pos := call.Args[od.nparams-2].End()
+		return ", " + text, pos, pos, nil
+	default:
+		return text, call.Lparen + 1, call.Lparen + 1, nil
+	}
+}
+
+// importQualifier returns a qualifier for references to packages from
+// file f of package pkg, and a function that reports whether any
+// qualified package is not imported by f.
+func importQualifier(f *ast.File, pkg *types.Package) (types.Qualifier, func() bool) {
+	names := make(map[string]string) // maps import path to local name, if renamed
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name == nil {
+			names[path] = ""
+		} else if imp.Name.Name != "_" {
+			names[path] = imp.Name.Name
+		}
+	}
+	missing := false
+	qual := func(p *types.Package) string {
+		if p.Path() == pkg.Path() {
+			return ""
+		}
+		name, ok := names[p.Path()]
+		switch {
+		case !ok:
+			missing = true
+		case name == ".":
+			return ""
+		case name != "":
+			return name
+		}
+		return p.Name()
+	}
+	return qual, func() bool { return missing }
+}
@@ -795,6 +795,17 @@ func hover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng pr
 	if sym := StdSymbolOf(obj); sym != nil && sym.Version > 0 {
 		footer = fmt.Sprintf("Added in %v", sym.Version)
 	}
+	if fn, ok := obj.(*types.Func); ok {
+		coverage, err := funcCoverage(ctx, snapshot, pkg.FileSet(), fn.Origin())
+		if err != nil {
+			event.Error(ctx, "computing function coverage", err)
+		} else if coverage != "" {
+			if footer != "" {
+				footer += "\n\n"
+			}
+			footer += coverage
+		}
+	}
 
 	return *hoverRange, &hoverResult{
 		Synopsis:          doc.Synopsis(docText),
@@ -1348,6 +1359,31 @@ func HoverDocForObject(ctx context.Context, snapshot *cache.Snapshot, fset *toke
 	return chooseDocComment(pgf, decl, spec, field, assign), nil
 }
 
+// HoverDocAt returns the best doc comment for the object declared at
+// the given offset of the specified file.
+func HoverDocAt(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI, offset int) (*ast.CommentGroup, error) {
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := safetoken.Pos(pgf.Tok, offset)
+	if err != nil {
+		return nil, err
+	}
+	return HoverDocInFile(pgf, pos), nil
+}
+
+// HoverDocInFile returns the best doc comment for the object declared
+// at pos in the already parsed file pgf.
+func HoverDocInFile(pgf *parsego.File, pos token.Pos) *ast.CommentGroup {
+	decl, spec, field, assign := findDeclInfo(pgf, pos)
+	return chooseDocComment(pgf, decl, spec, field, assign)
+}
+
 // chooseDocComment returns the best doc comment for the given declaration
 // information.
 func chooseDocComment(pgf *parsego.File, decl ast.Decl, spec ast.Spec, field *ast.Field, assign *ast.AssignStmt) *ast.CommentGroup {
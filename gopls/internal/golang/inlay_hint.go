@@ -48,7 +48,16 @@ func InlayHint(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pR
 			enabledHints = append(enabledHints, fn)
 		}
 	}
-	if len(enabledHints) == 0 {
+	var enabledCompilerHints []compilerInlayHintFunc
+	for hint, enabled := range inlayHintOptions.Hints {
+		if !enabled {
+			continue
+		}
+		if fn, ok := compilerInlayHints[hint]; ok {
+			enabledCompilerHints = append(enabledCompilerHints, fn)
+		}
+	}
+	if len(enabledHints) == 0 && len(enabledCompilerHints) == 0 {
 		return nil, nil
 	}
 
@@ -74,6 +83,19 @@ func InlayHint(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pR
 		for _, fn := range enabledHints {
 			fn(info, pgf, qual, curSubrange, add)
 		}
+		if len(enabledCompilerHints) > 0 {
+			// Failure to run the compiler (e.g. because the
+			// package does not build) is not an error.
+			log, err := compilerOptLog(ctx, snapshot, pgf.URI.Dir())
+			if err != nil {
+				event.Error(ctx, "computing compiler optimization details", err)
+			}
+			if decisions := compilerDecisions(pkg, log); len(decisions) > 0 {
+				for _, fn := range enabledCompilerHints {
+					fn(pkg, pgf, decisions, curSubrange, add)
+				}
+			}
+		}
 	}
 	return hints, nil
 }
@@ -91,6 +113,123 @@ var allInlayHints = map[settings.InlayHint]inlayHintFunc{
 	settings.IgnoredError:               ignoredError,
 }
 
+// A compilerInlayHintFunc computes inlay hints from the compiler's
+// decisions about the package, indexed by position and code.
+type compilerInlayHintFunc func(pkg *cache.Package, pgf *parsego.File, decisions map[compilerDecision]string, cur inspector.Cursor, add func(protocol.InlayHint))
+
+// compilerInlayHints are the inlay hints derived from the compiler's
+// log of optimization decisions (see compileropt.go), which are
+// computed only if requested, as they require running the compiler.
+var compilerInlayHints = map[settings.InlayHint]compilerInlayHintFunc{
+	settings.HeapEscapes:  heapEscapes,
+	settings.InlinedCalls: inlinedCalls,
+}
+
+// A compilerDecision identifies a decision of the compiler, such as
+// "escape" or "canInlineFunction", at a position in a file.
+type compilerDecision struct {
+	pos  token.Pos
+	code string
+}
+
+// compilerDecisions indexes the compiler's log of optimization
+// decisions about the files of a package, mapping each to its details.
+func compilerDecisions(pkg *cache.Package, log map[protocol.DocumentURI][]*cache.Diagnostic) map[compilerDecision]string {
+	decisions := make(map[compilerDecision]string)
+	for _, pgf := range pkg.CompiledGoFiles() {
+		for _, diag := range log[pgf.URI] {
+			// The compiler reports 1-based UTF-8 columns, which
+			// parseDetailsFile converts to 0-based byte offsets.
+			line, col := int(diag.Range.Start.Line)+1, int(diag.Range.Start.Character)
+			if line > pgf.Tok.LineCount() {
+				continue // log is stale
+			}
+			pos := pgf.Tok.LineStart(line) + token.Pos(col)
+			if pos > pgf.File.FileEnd {
+				continue
+			}
+			code, details := splitDecision(diag.Message)
+			decisions[compilerDecision{pos, code}] = details
+		}
+	}
+	return decisions
+}
+
+func heapEscapes(pkg *cache.Package, pgf *parsego.File, decisions map[compilerDecision]string, cur inspector.Cursor, add func(protocol.InlayHint)) {
+	for curExpr := range cur.Preorder() {
+		expr, ok := curExpr.Node().(ast.Expr)
+		if !ok {
+			continue
+		}
+		_, escape := decisions[compilerDecision{expr.Pos(), "escape"}]
+		_, escapes := decisions[compilerDecision{expr.Pos(), "escapes"}]
+		if !escape && !escapes {
+			continue
+		}
+		// Annotate only the outermost expression at the
+		// position of the decision, e.g. &T{} not T.
+		if parent, ok := curExpr.Parent().Node().(ast.Expr); ok && parent.Pos() == expr.Pos() {
+			continue
+		}
+		end, err := pgf.PosPosition(expr.End())
+		if err != nil {
+			continue
+		}
+		add(protocol.InlayHint{
+			Position:    end,
+			Label:       labelPart("escapes to heap"),
+			PaddingLeft: true,
+		})
+	}
+}
+
+func inlinedCalls(pkg *cache.Package, pgf *parsego.File, decisions map[compilerDecision]string, cur inspector.Cursor, add func(protocol.InlayHint)) {
+	// The log records, for each function declared in the package,
+	// whether it can be inlined, and, at each call that is not
+	// inlined despite that, why not. The position of a function is
+	// that of the token following "func": its name, or, for a
+	// method, its receiver.
+	info := pkg.TypesInfo()
+	canInline := make(map[*types.Func]bool)
+	for _, pgf := range pkg.CompiledGoFiles() {
+		for _, decl := range pgf.File.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok {
+				pos := decl.Name.Pos()
+				if decl.Recv != nil {
+					pos = decl.Recv.Opening
+				}
+				if _, ok := decisions[compilerDecision{pos, "canInlineFunction"}]; ok {
+					if fn, ok := info.Defs[decl.Name].(*types.Func); ok {
+						canInline[fn] = true
+					}
+				}
+			}
+		}
+	}
+	if len(canInline) == 0 {
+		return
+	}
+	for curCall := range cur.Preorder((*ast.CallExpr)(nil)) {
+		call := curCall.Node().(*ast.CallExpr)
+		fn := typeutil.StaticCallee(info, call)
+		if fn == nil || !canInline[fn.Origin()] {
+			continue
+		}
+		if _, ok := decisions[compilerDecision{call.Lparen, "cannotInlineCall"}]; ok {
+			continue
+		}
+		end, err := pgf.PosPosition(call.End())
+		if err != nil {
+			continue
+		}
+		add(protocol.InlayHint{
+			Position:    end,
+			Label:       labelPart("inlined"),
+			PaddingLeft: true,
+		})
+	}
+}
+
 func parameterNames(info *types.Info, pgf *parsego.File, qual types.Qualifier, cur inspector.Cursor, add func(protocol.InlayHint)) {
 	for curCall := range cur.Preorder((*ast.CallExpr)(nil)) {
 		callExpr := curCall.Node().(*ast.CallExpr)
@@ -10,6 +10,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/types"
+	"strings"
 
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/types/typeutil"
@@ -27,6 +28,11 @@ import (
 // inlineAllCalls inlines all calls to the original function declaration
 // described by callee, returning the resulting modified file content.
 //
+// Only calls in workspace packages are inlined: the references in
+// other packages, such as the callee's own package when it is a
+// dependency in the module cache, are read-only, and are returned as
+// skipped, for the caller to report.
+//
 // inlining everything is currently an expensive operation: it involves re-type
 // checking every package that contains a potential call, as reported by
 // References. In cases where there are multiple calls per file, inlineAllCalls
@@ -48,21 +54,21 @@ import (
 //
 // The code below notes where are assumptions are made that only hold true in
 // the case of parameter removal (annotated with 'Assumption:')
-func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, origDecl *ast.FuncDecl, callee *inline.Callee, post func([]byte) []byte, opts *inline.Options) (_ map[protocol.DocumentURI][]byte, inlineErr error) {
+func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, origDecl *ast.FuncDecl, callee *inline.Callee, post func([]byte) []byte, opts *inline.Options) (_ map[protocol.DocumentURI][]byte, skipped []protocol.Location, inlineErr error) {
 	// Collect references.
 	var refs []protocol.Location
 	{
 		funcRng, err := pgf.Mapper.PosRange(pgf.Tok, origDecl.Name.NamePos, origDecl.Name.NamePos)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		fh, err := snapshot.ReadFile(ctx, pgf.URI)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		refs, err = References(ctx, snapshot, fh, funcRng, false)
 		if err != nil {
-			return nil, fmt.Errorf("finding references to rewrite: %v", err)
+			return nil, nil, fmt.Errorf("finding references to rewrite: %v", err)
 		}
 	}
 
@@ -80,19 +86,28 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 	)
 	{
 		needPkgs := make(map[PackageID]struct{})
+		var workspaceRefs []protocol.Location
 		for _, ref := range refs {
 			md, err := snapshot.NarrowestMetadataForFile(ctx, ref.URI)
 			if err != nil {
-				return nil, fmt.Errorf("finding ref metadata: %v", err)
+				return nil, nil, fmt.Errorf("finding ref metadata: %v", err)
+			}
+			// Calls outside the workspace, such as within a dependency
+			// in the module cache that declares the callee, are read-only.
+			if !snapshot.IsWorkspacePackage(md.ID) {
+				skipped = append(skipped, ref)
+				continue
 			}
+			workspaceRefs = append(workspaceRefs, ref)
 			pkgForRef[ref] = md.ID
 			needPkgs[md.ID] = struct{}{}
 		}
+		refs = workspaceRefs
 		pkgIDs := moremaps.KeySlice(needPkgs)
 
 		refPkgs, err := snapshot.TypeCheck(ctx, pkgIDs...)
 		if err != nil {
-			return nil, fmt.Errorf("type checking reference packages: %v", err)
+			return nil, nil, fmt.Errorf("type checking reference packages: %v", err)
 		}
 
 		for _, p := range refPkgs {
@@ -127,12 +142,12 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 		refpkg := pkgs[pkgForRef[ref]]
 		pgf, err := refpkg.File(ref.URI)
 		if err != nil {
-			return nil, bug.Errorf("finding %s in %s: %v", ref.URI, refpkg.Metadata().ID, err)
+			return nil, nil, bug.Errorf("finding %s in %s: %v", ref.URI, refpkg.Metadata().ID, err)
 		}
 
 		start, end, err := pgf.RangePos(ref.Range)
 		if err != nil {
-			return nil, err // e.g. invalid range
+			return nil, nil, err // e.g. invalid range
 		}
 
 		// Look for the surrounding call expression.
@@ -155,7 +170,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			//    use(f)
 			// is replaced by
 			//    use(func(...) { f(...) })
-			return nil, fmt.Errorf("cannot inline: found non-call function reference %v", ref)
+			return nil, nil, fmt.Errorf("cannot inline: found non-call function reference %v", ref)
 		}
 
 		// Heuristic: ignore references that overlap with type checker errors, as they may
@@ -181,7 +196,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			obj.Pkg() == nil ||
 			obj.Pkg().Path() != string(pkg.Metadata().PkgPath) {
 
-			return nil, bug.Errorf("cannot inline: corrupted reference %v", ref)
+			return nil, nil, bug.Errorf("cannot inline: corrupted reference %v", ref)
 		}
 
 		callInfo, ok := refsByFile[ref.URI]
@@ -218,7 +233,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 		// the next call (counting from top-to-bottom) does not work.
 		for i := range calls {
 			if i > 0 && calls[i-1].End() > calls[i].Pos() {
-				return nil, fmt.Errorf("%s: can't inline overlapping call %s", uri, types.ExprString(calls[i-1]))
+				return nil, nil, fmt.Errorf("%s: can't inline overlapping call %s", uri, types.ExprString(calls[i-1]))
 			}
 		}
 
@@ -234,7 +249,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			}
 			res, err := inline.Inline(caller, callee, opts)
 			if err != nil {
-				return nil, fmt.Errorf("inlining failed: %v", err)
+				return nil, nil, fmt.Errorf("inlining failed: %v", err)
 			}
 
 			// applyEdits transforms content by applying the specified edits
@@ -263,7 +278,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 
 			content, err = applyEdits(content, res.Edits)
 			if err != nil {
-				return nil, fmt.Errorf("applying inliner edits failed: %v", err)
+				return nil, nil, fmt.Errorf("applying inliner edits failed: %v", err)
 			}
 
 			if post != nil {
@@ -286,7 +301,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 
 			file, err = parser.ParseFile(fset, uri.Path(), content, parser.ParseComments|parser.SkipObjectResolution)
 			if err != nil {
-				return nil, bug.Errorf("inlined file failed to parse: %v", err)
+				return nil, nil, bug.Errorf("inlined file failed to parse: %v", err)
 			}
 
 			// After inlining one call with a removed parameter, the package will
@@ -304,7 +319,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			}
 			tpkg, tinfo, err = reTypeCheck(logf, callInfo.pkg, map[protocol.DocumentURI]*ast.File{uri: file}, true)
 			if err != nil {
-				return nil, bug.Errorf("type checking after inlining failed: %v", err)
+				return nil, nil, bug.Errorf("type checking after inlining failed: %v", err)
 			}
 
 			// Collect calls to the target function in the modified declaration.
@@ -333,7 +348,7 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			// correlate the before and after syntax?
 			switch {
 			case len(calls2) > len(calls):
-				return nil, fmt.Errorf("inlining increased calls %d->%d, possible recursive call? content:\n%s", len(calls), len(calls2), content)
+				return nil, nil, fmt.Errorf("inlining increased calls %d->%d, possible recursive call? content:\n%s", len(calls), len(calls2), content)
 			case len(calls2) < len(calls):
 				calls = calls2
 			case len(calls2) == len(calls):
@@ -344,5 +359,72 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 
 		result[callInfo.pgf.URI] = content
 	}
-	return result, nil
+	return result, skipped, nil
+}
+
+// InlineAllCalls inlines all calls, throughout the workspace, to the
+// function statically called at the selected range. The function may
+// be declared in a dependency whose source is read-only, such as a
+// module in the module cache, which is useful when migrating away
+// from deprecated helpers of another module.
+//
+// It returns the changes to the affected files, a unified diff of
+// them, in order of file name, and the calls outside the workspace,
+// which are not inlined.
+func InlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range) ([]protocol.DocumentChange, string, []protocol.Location, error) {
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	_, fn, err := enclosingStaticCall(pkg, pgf, start, end)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	calleePkg, calleePGF, calleePos, err := NarrowestDeclaringPackage(ctx, snapshot, pkg, fn)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	var calleeDecl *ast.FuncDecl
+	for _, decl := range calleePGF.File.Decls {
+		if decl, ok := decl.(*ast.FuncDecl); ok && decl.Name.Pos() == calleePos {
+			calleeDecl = decl
+			break
+		}
+	}
+	if calleeDecl == nil {
+		return nil, "", nil, fmt.Errorf("can't find callee")
+	}
+
+	logf := logger(ctx, "inliner", snapshot.Options().VerboseOutput)
+	callee, err := inline.AnalyzeCallee(logf, calleePkg.FileSet(), calleePkg.Types(), calleePkg.TypesInfo(), calleeDecl, calleePGF.Src)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	newContent, skipped, err := inlineAllCalls(ctx, snapshot, calleePkg, calleePGF, calleeDecl, callee, nil, &inline.Options{Logf: logf})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	changes, err := documentChanges(ctx, snapshot, newContent)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var unified strings.Builder
+	for uri, after := range moremaps.Sorted(newContent) {
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		before, err := fh.Content()
+		if err != nil {
+			return nil, "", nil, err
+		}
+		filename := uri.Path()
+		u, err := diff.ToUnified(filename+".orig", filename, string(before), diff.Bytes(before, after), diff.DefaultContextLines)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		unified.WriteString(u)
+	}
+	return changes, unified.String(), skipped, nil
 }
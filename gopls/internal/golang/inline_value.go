@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/moreiters"
+)
+
+// InlineValues returns the inline values to be displayed by a debugger
+// stopped at the specified location within the visible range rng of a
+// file.
+//
+// The result contains a variable lookup for each reference to a
+// variable that is in scope at the stopped location, within the
+// innermost function enclosing it, up to the end of the last line of
+// the stopped location (where the debugger typically displays the
+// values).
+// Package-level variables are omitted, since debuggers do not look
+// them up by their unqualified names.
+func InlineValues(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng, stopped protocol.Range) ([]protocol.InlineValue, error) {
+	ctx, done := event.Start(ctx, "golang.InlineValues")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, fmt.Errorf("getting package for InlineValues: %w", err)
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	stopStart, stopEnd, err := pgf.RangePos(stopped)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the function in which execution stopped.
+	cur, _, _, _ := astutil.Select(pgf.Cursor(), stopStart, stopStart) // can't fail: pgf contains pos
+	curFn, ok := moreiters.First(cur.Enclosing((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)))
+	if !ok {
+		return nil, nil // not stopped within a function
+	}
+	// Values are displayed on the last line of the stopped location,
+	// so include references anywhere within it.
+	if line := safetoken.Line(pgf.Tok, stopEnd); line < pgf.Tok.LineCount() {
+		stopEnd = pgf.Tok.LineStart(line + 1)
+	}
+	start = max(start, curFn.Node().Pos())
+	end = min(end, curFn.Node().End(), stopEnd)
+
+	info := pkg.TypesInfo()
+	var values []protocol.InlineValue
+	for curId := range curFn.Preorder((*ast.Ident)(nil)) {
+		id := curId.Node().(*ast.Ident)
+		if id.Pos() < start || id.End() > end {
+			continue
+		}
+		v, ok := info.ObjectOf(id).(*types.Var)
+		if !ok || v.IsField() || v.Name() == "_" || v.Pos() > stopStart {
+			continue
+		}
+		if scope := v.Parent(); scope == nil || scope == v.Pkg().Scope() || !scope.Contains(stopStart) {
+			continue // not a local variable in scope at the stopped location
+		}
+		idRange, err := pgf.NodeRange(id)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, protocol.InlineValue{
+			Value: protocol.InlineValueVariableLookup{
+				Range:               idRange,
+				VariableName:        id.Name,
+				CaseSensitiveLookup: true,
+			},
+		})
+	}
+	return values, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/event"
+)
+
+// identifierWordPattern is the pattern of an ASCII Go identifier, which
+// the client uses to decide whether an edit to a linked range is valid.
+// Clients compile it as a JavaScript RegExp without the u flag, in which
+// Unicode classes such as \p{L} are not supported, so typing a non-ASCII
+// letter ends linked editing.
+const identifierWordPattern = `[A-Za-z_][A-Za-z0-9_]*`
+
+// tagNameWordPattern is the pattern of the name in a struct tag value,
+// such as id in `json:"id,omitempty"`.
+const tagNameWordPattern = `[^\s",:` + "`" + `]+`
+
+// LinkedEditingRanges returns the ranges of all occurrences of the
+// local variable or label identified at the specified position, for use
+// by an editor that supports linked editing: typing in one of them
+// changes all of them, so that a local can be renamed without a
+// textDocument/rename round trip.
+//
+// Since all occurrences of a local are within the file that declares it,
+// the result is found by the same logic as textDocument/highlight.
+// It returns nil for any other kind of identifier, since a change to it
+// may require edits to other files or checks for conflicts.
+//
+// Within a struct field tag, it returns the ranges of the names that
+// the tag's keys give the field, if they are the same as the name at
+// the specified position: in `json:"id" yaml:"id"`, both ids are linked.
+func LinkedEditingRanges(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position) (*protocol.LinkedEditingRanges, error) {
+	ctx, done := event.Start(ctx, "golang.LinkedEditingRanges")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, fmt.Errorf("getting package for LinkedEditingRanges: %w", err)
+	}
+	pos, err := pgf.PositionPos(pp)
+	if err != nil {
+		return nil, err
+	}
+	cur, _, _, _ := astutil.Select(pgf.Cursor(), pos, pos) // can't fail: pgf contains pos
+	if lit, ok := cur.Node().(*ast.BasicLit); ok {
+		if field, ok := cur.Parent().Node().(*ast.Field); ok && field.Tag == lit {
+			return linkedTagNames(pgf, lit, pos)
+		}
+	}
+	id, ok := cur.Node().(*ast.Ident)
+	if !ok {
+		return nil, nil // not an identifier
+	}
+
+	info := pkg.TypesInfo()
+	obj := info.ObjectOf(id)
+	switch obj := obj.(type) {
+	case *types.Var:
+		if obj.IsField() || obj.Parent() == nil || obj.Parent() == obj.Pkg().Scope() {
+			return nil, nil // not a local variable
+		}
+	case *types.Label:
+	default:
+		return nil, nil
+	}
+
+	// The object must be declared by an identifier in this file.
+	// This excludes the implicit variables of type switch cases,
+	// whose occurrences are not linked to those in other cases.
+	if curDecl, ok := pgf.Cursor().FindByPos(obj.Pos(), obj.Pos()+token.Pos(len(obj.Name()))); !ok {
+		return nil, nil
+	} else if decl, ok := curDecl.Node().(*ast.Ident); !ok || info.Defs[decl] != obj {
+		return nil, nil
+	}
+
+	result := make(map[astutil.Range]protocol.DocumentHighlightKind)
+	highlightIdentifier(cur, info, result)
+
+	var ranges []protocol.Range
+	for rng := range result {
+		if rng.End()-rng.Pos() != token.Pos(len(id.Name)) {
+			continue // not an occurrence of the identifier itself
+		}
+		rng, err := pgf.NodeRange(rng)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, rng)
+	}
+	slices.SortFunc(ranges, protocol.CompareRange)
+	return &protocol.LinkedEditingRanges{
+		Ranges:      ranges,
+		WordPattern: identifierWordPattern,
+	}, nil
+}
+
+// linkedTagNames returns the linked editing ranges of the names in the
+// struct field tag lit, such as id in `json:"id,omitempty"`, that are
+// the same as the one at pos. Only raw string tags are supported, as
+// in other tags a name may contain escape sequences.
+func linkedTagNames(pgf *parsego.File, lit *ast.BasicLit, pos token.Pos) (*protocol.LinkedEditingRanges, error) {
+	if !strings.HasPrefix(lit.Value, "`") {
+		return nil, nil // not a raw string
+	}
+	names := tagNames(lit.Value[1 : len(lit.Value)-1])
+	i := slices.IndexFunc(names, func(name tagName) bool {
+		start := lit.ValuePos + 1 + token.Pos(name.offset)
+		return start <= pos && pos <= start+token.Pos(len(name.text))
+	})
+	if i < 0 || names[i].text == "" {
+		return nil, nil // not within a name
+	}
+
+	var ranges []protocol.Range
+	for _, name := range names {
+		if name.text == names[i].text {
+			start := lit.ValuePos + 1 + token.Pos(name.offset)
+			rng, err := pgf.PosRange(start, start+token.Pos(len(name.text)))
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, rng)
+		}
+	}
+	return &protocol.LinkedEditingRanges{
+		Ranges:      ranges,
+		WordPattern: tagNameWordPattern,
+	}, nil
+}
+
+// A tagName is the name part of the value of a struct tag key.
+type tagName struct {
+	offset int    // offset of the name within the tag
+	text   string // possibly empty, as in `json:",omitempty"`
+}
+
+// tagNames returns the names of the values of the keys of a struct tag,
+// which uses the conventional key:"value" syntax described at
+// [reflect.StructTag]. It stops at the first malformed key or value.
+func tagNames(tag string) []tagName {
+	var names []tagName
+	for i := 0; ; {
+		// Skip leading space.
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		// Scan the key, up to the colon and quote.
+		j := i
+		for j < len(tag) && tag[j] > ' ' && tag[j] != ':' && tag[j] != '"' && tag[j] != 0x7f {
+			j++
+		}
+		if j == i || j+1 >= len(tag) || tag[j] != ':' || tag[j+1] != '"' {
+			return names
+		}
+		// Scan the quoted value.
+		start := j + 2
+		k := start
+		for k < len(tag) && tag[k] != '"' {
+			if tag[k] == '\\' {
+				k++
+			}
+			k++
+		}
+		if k >= len(tag) {
+			return names
+		}
+		name, _, _ := strings.Cut(tag[start:k], ",")
+		names = append(names, tagName{start, name})
+		i = k + 1
+	}
+}
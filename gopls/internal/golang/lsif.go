@@ -0,0 +1,307 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the LSIF dump of the workspace (gopls index).
+// See https://microsoft.github.io/language-server-protocol/specifications/lsif/0.6.0/specification/.
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"slices"
+
+	"golang.org/x/tools/go/types/objectpath"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/internal/version"
+	"golang.org/x/tools/internal/event"
+)
+
+// WriteLSIF writes to w an LSIF dump of the workspace packages of the
+// snapshot, including their test files. The dump records, for each
+// symbol referenced by the packages, its definitions, references,
+// hover text, and moniker, so that references to the symbols of the
+// workspace from dumps of other modules can be resolved. The version
+// of the main modules is modVersion, which may be empty.
+//
+// The dump is a sequence of vertices and edges in JSON, one per line.
+func WriteLSIF(ctx context.Context, snapshot *cache.Snapshot, w io.Writer, modVersion string) error {
+	ctx, done := event.Start(ctx, "golang.WriteLSIF")
+	defer done()
+
+	mps, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	// Each file is indexed once, as part of the first package that
+	// includes it: test variants, which include the test files, come
+	// before the packages they augment.
+	mps = slices.DeleteFunc(mps, (*metadata.Package).IsIntermediateTestVariant)
+	slices.SortFunc(mps, func(x, y *metadata.Package) int {
+		if (x.ForTest == "") != (y.ForTest == "") {
+			if x.ForTest != "" {
+				return -1
+			}
+			return +1
+		}
+		return cmp.Compare(x.ID, y.ID)
+	})
+	ids := make([]PackageID, len(mps))
+	for i, mp := range mps {
+		ids[i] = mp.ID
+	}
+	pkgs, err := snapshot.TypeCheck(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	d := &lsifDumper{
+		enc:        json.NewEncoder(w),
+		mg:         snapshot.MetadataGraph(),
+		modVersion: modVersion,
+		symbols:    make(map[string]*lsifSymbol),
+		pkgInfos:   make(map[monikerModule]int),
+	}
+	d.vertex("metaData", map[string]any{
+		"version":          "0.6.0",
+		"projectRoot":      snapshot.Folder(),
+		"positionEncoding": "utf-16",
+		"toolInfo":         map[string]any{"name": "gopls", "version": version.Version()},
+	})
+	project := d.vertex("project", map[string]any{"kind": "go"})
+
+	var (
+		documents []int
+		seen      = make(map[protocol.DocumentURI]bool)
+	)
+	for _, pkg := range pkgs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, pgf := range pkg.CompiledGoFiles() {
+			if seen[pgf.URI] {
+				continue
+			}
+			seen[pgf.URI] = true
+			doc, err := d.document(pkg, pgf)
+			if err != nil {
+				return err
+			}
+			documents = append(documents, doc)
+		}
+	}
+	for _, sym := range d.order {
+		d.results(sym)
+	}
+	if len(documents) > 0 {
+		d.edge("contains", project, map[string]any{"inVs": documents})
+	}
+	return d.err
+}
+
+// An lsifDumper writes the elements of an LSIF dump.
+type lsifDumper struct {
+	enc        *json.Encoder
+	err        error // first encoding error
+	lastID     int
+	mg         *metadata.Graph
+	modVersion string
+
+	symbols  map[string]*lsifSymbol // keyed by symbolKey
+	order    []*lsifSymbol          // in order of appearance
+	pkgInfos map[monikerModule]int  // packageInformation vertices
+}
+
+// An lsifSymbol holds the ranges of the definitions and references of a
+// symbol, which share its result set.
+type lsifSymbol struct {
+	obj       types.Object
+	pkg       *types.Package // package in which obj was first seen
+	resultSet int
+	defs      []lsifRange
+	refs      []lsifRange
+}
+
+// An lsifRange is a range vertex within a document vertex.
+type lsifRange struct {
+	document, rng int
+}
+
+// emit writes an element of the dump and returns its ID.
+func (d *lsifDumper) emit(typ, label string, props map[string]any) int {
+	d.lastID++
+	elem := map[string]any{"id": d.lastID, "type": typ, "label": label}
+	for k, v := range props {
+		elem[k] = v
+	}
+	if err := d.enc.Encode(elem); err != nil && d.err == nil {
+		d.err = err
+	}
+	return d.lastID
+}
+
+func (d *lsifDumper) vertex(label string, props map[string]any) int {
+	return d.emit("vertex", label, props)
+}
+
+// edge emits an edge from outV to the vertex inV or vertices inVs in props.
+func (d *lsifDumper) edge(label string, outV int, props map[string]any) {
+	props["outV"] = outV
+	d.emit("edge", label, props)
+}
+
+// document emits the document vertex for a file of pkg, and the range
+// vertices of its identifiers.
+func (d *lsifDumper) document(pkg *cache.Package, pgf *parsego.File) (int, error) {
+	doc := d.vertex("document", map[string]any{"uri": pgf.URI, "languageId": "go"})
+
+	info := pkg.TypesInfo()
+	var (
+		ranges []int
+		err    error
+	)
+	ast.Inspect(pgf.File, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || err != nil {
+			return err == nil
+		}
+		obj, isDef := info.Defs[id], true
+		if obj == nil {
+			obj, isDef = info.Uses[id], false
+		}
+		// Package names are local to a file: they are not indexed.
+		if obj == nil || obj.Pkg() == nil || obj.Name() == "_" || is[*types.PkgName](obj) {
+			return true
+		}
+		key, ok := symbolKey(pkg, obj)
+		if !ok {
+			return true
+		}
+		sym := d.symbols[key]
+		if sym == nil {
+			sym = &lsifSymbol{
+				obj:       obj,
+				pkg:       pkg.Types(),
+				resultSet: d.vertex("resultSet", nil),
+			}
+			d.symbols[key] = sym
+			d.order = append(d.order, sym)
+		}
+		var rng protocol.Range
+		rng, err = pgf.NodeRange(id)
+		if err != nil {
+			return false
+		}
+		r := d.vertex("range", map[string]any{"start": rng.Start, "end": rng.End})
+		d.edge("next", r, map[string]any{"inV": sym.resultSet})
+		ranges = append(ranges, r)
+		if isDef {
+			sym.defs = append(sym.defs, lsifRange{doc, r})
+		} else {
+			sym.refs = append(sym.refs, lsifRange{doc, r})
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(ranges) > 0 {
+		d.edge("contains", doc, map[string]any{"inVs": ranges})
+	}
+	return doc, nil
+}
+
+// symbolKey returns a key that identifies obj across the packages of
+// the dump, in which it may be represented by distinct objects.
+// It reports false if the declaration of obj is unknown.
+func symbolKey(pkg *cache.Package, obj types.Object) (string, bool) {
+	obj = origin(obj)
+	if path, err := objectpath.For(obj); err == nil {
+		return obj.Pkg().Path() + "#" + string(path), true
+	}
+	// A symbol without an object path, such as a local variable,
+	// is identified by the position of its declaration.
+	tf := pkg.FileSet().File(obj.Pos())
+	if tf == nil {
+		return "", false
+	}
+	offset, err := safetoken.Offset(tf, obj.Pos())
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s:%d", tf.Name(), offset), true
+}
+
+// results emits the result vertices of a symbol: its definitions,
+// references, hover text, and moniker.
+func (d *lsifDumper) results(sym *lsifSymbol) {
+	if len(sym.defs) > 0 {
+		defResult := d.vertex("definitionResult", nil)
+		d.edge("textDocument/definition", sym.resultSet, map[string]any{"inV": defResult})
+		d.items(defResult, sym.defs, "")
+	}
+
+	refResult := d.vertex("referenceResult", nil)
+	d.edge("textDocument/references", sym.resultSet, map[string]any{"inV": refResult})
+	d.items(refResult, sym.defs, "definitions")
+	d.items(refResult, sym.refs, "references")
+
+	hover := d.vertex("hoverResult", map[string]any{
+		"result": map[string]any{
+			"contents": protocol.MarkupContent{
+				Kind:  protocol.Markdown,
+				Value: "```go\n" + types.ObjectString(sym.obj, types.RelativeTo(sym.pkg)) + "\n```",
+			},
+		},
+	})
+	d.edge("textDocument/hover", sym.resultSet, map[string]any{"inV": hover})
+
+	m, mod, ok := symbolMoniker(d.mg, sym.pkg, sym.obj, d.modVersion)
+	if !ok {
+		return
+	}
+	moniker := d.vertex("moniker", map[string]any{
+		"scheme":     m.Scheme,
+		"identifier": m.Identifier,
+		"unique":     m.Unique,
+		"kind":       m.Kind,
+	})
+	d.edge("moniker", sym.resultSet, map[string]any{"inV": moniker})
+	pkgInfo, ok := d.pkgInfos[mod]
+	if !ok {
+		props := map[string]any{"name": mod.Path, "manager": "gomod"}
+		if mod.Version != "" {
+			props["version"] = mod.Version
+		}
+		pkgInfo = d.vertex("packageInformation", props)
+		d.pkgInfos[mod] = pkgInfo
+	}
+	d.edge("packageInformation", moniker, map[string]any{"inV": pkgInfo})
+}
+
+// items emits the item edges from a result to the given ranges,
+// one per document.
+func (d *lsifDumper) items(result int, ranges []lsifRange, property string) {
+	for i := 0; i < len(ranges); {
+		doc := ranges[i].document
+		var inVs []int
+		for ; i < len(ranges) && ranges[i].document == doc; i++ {
+			inVs = append(inVs, ranges[i].rng)
+		}
+		props := map[string]any{"inVs": inVs, "document": doc}
+		if property != "" {
+			props["property"] = property
+		}
+		d.edge("item", result, props)
+	}
+}
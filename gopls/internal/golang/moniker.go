@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the monikers of Go symbols (textDocument/moniker),
+// which identify them across the boundaries of a workspace.
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/types/objectpath"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// monikerScheme is the scheme of the monikers of Go symbols.
+const monikerScheme = "go"
+
+// Moniker returns the moniker of the symbol identified at the specified
+// position, or none if the symbol is local to a function or predeclared.
+//
+// The moniker's identifier has the form
+//
+//	module[@version] package[#objectpath]
+//
+// where the object path (see [objectpath.For]) is absent for a
+// reference to an imported package. The version is absent if unknown,
+// for example for the packages of the workspace; "std" is the module
+// of the standard library.
+func Moniker(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp protocol.Position) ([]protocol.Moniker, error) {
+	ctx, done := event.Start(ctx, "golang.Moniker")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, fmt.Errorf("getting package for Moniker: %w", err)
+	}
+	pos, err := pgf.PositionPos(pp)
+	if err != nil {
+		return nil, err
+	}
+	cur, ok := pgf.Cursor().FindByPos(pos, pos)
+	if !ok {
+		return nil, nil
+	}
+	objects, err := objectsAt(pkg.TypesInfo(), cur)
+	if err != nil {
+		return nil, nil // no symbol at position
+	}
+
+	var monikers []protocol.Moniker
+	for _, o := range objects {
+		m, _, ok := symbolMoniker(snapshot.MetadataGraph(), pkg.Types(), o.obj, "")
+		if ok {
+			monikers = append(monikers, m)
+		}
+	}
+	return monikers, nil
+}
+
+// symbolMoniker returns the moniker of the symbol obj as seen from
+// package pkg, and the module that declares it. The version of the
+// main modules is mainVersion, which may be empty.
+//
+// It reports false if obj has no moniker, because it is local to a
+// function, predeclared, or in a package outside any module.
+func symbolMoniker(mg *metadata.Graph, pkg *types.Package, obj types.Object, mainVersion string) (protocol.Moniker, monikerModule, bool) {
+	var (
+		pkgPath string
+		objPath objectpath.Path
+		kind    protocol.MonikerKind
+	)
+	if pkgName, ok := obj.(*types.PkgName); ok {
+		pkgPath = pkgName.Imported().Path()
+		kind = protocol.Import
+	} else {
+		if obj.Pkg() == nil {
+			return protocol.Moniker{}, monikerModule{}, false // predeclared
+		}
+		path, err := objectpath.For(origin(obj))
+		if err != nil {
+			return protocol.Moniker{}, monikerModule{}, false // e.g. a local variable
+		}
+		pkgPath, objPath = obj.Pkg().Path(), path
+		switch {
+		case obj.Pkg().Path() != pkg.Path():
+			kind = protocol.Import
+		case obj.Exported():
+			kind = protocol.Export
+		default:
+			kind = protocol.Local
+		}
+	}
+
+	mod, ok := packageModule(mg, metadata.PackagePath(pkgPath), mainVersion)
+	if !ok {
+		return protocol.Moniker{}, monikerModule{}, false
+	}
+	id := mod.Path
+	if mod.Version != "" {
+		id += "@" + mod.Version
+	}
+	id += " " + pkgPath
+	if objPath != "" {
+		id += "#" + string(objPath)
+	}
+	return protocol.Moniker{
+		Scheme:     monikerScheme,
+		Identifier: id,
+		Unique:     protocol.Scheme,
+		Kind:       &kind,
+	}, mod, true
+}
+
+// A monikerModule identifies the module of a symbol with a moniker.
+type monikerModule struct {
+	Path, Version string
+}
+
+// packageModule returns the module of the package with the given path.
+// The module of the standard library is "std".
+func packageModule(mg *metadata.Graph, pkgPath metadata.PackagePath, mainVersion string) (monikerModule, bool) {
+	for _, mp := range mg.ForPackagePath[pkgPath] {
+		if metadata.IsCommandLineArguments(mp.ID) {
+			continue
+		}
+		if mod := mp.Module; mod != nil {
+			version := mod.Version
+			if mod.Main {
+				version = mainVersion
+			}
+			return monikerModule{mod.Path, version}, true
+		}
+		// The Module of standard packages may be missing (go.dev/issue/65816).
+		if first, _, _ := strings.Cut(string(pkgPath), "/"); !strings.Contains(first, ".") {
+			return monikerModule{Path: "std"}, true
+		}
+	}
+	return monikerModule{}, false
+}
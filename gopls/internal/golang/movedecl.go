@@ -0,0 +1,1040 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "Move declaration to another package" code
+// action (refactor.move.moveDecl).
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/edge"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/imports"
+	"golang.org/x/tools/internal/refactor"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// declToMove returns the package-level declaration whose name (or,
+// for a var, const, or type declaration, whose keyword) encloses the
+// selection [start, end), and those of its specs that are to be moved:
+// the selected one, or all of them if the selection is the keyword.
+// The specs of a func declaration are nil.
+//
+// It returns an error if there is no such declaration, or if it cannot
+// be moved on its own, as for a method or a constant whose value
+// depends on its position within its group.
+func declToMove(pgf *parsego.File, start, end token.Pos) (ast.Decl, []ast.Spec, error) {
+	within := func(n ast.Node) bool { return n.Pos() <= start && end <= n.End() }
+	for _, decl := range pgf.File.Decls {
+		if !within(decl) {
+			continue
+		}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !(decl.Pos() <= start && end <= decl.Name.End()) {
+				break
+			}
+			if decl.Recv != nil {
+				return nil, nil, fmt.Errorf("cannot move method %s on its own; move its receiver type", decl.Name.Name)
+			}
+			if decl.Name.Name == "init" {
+				return nil, nil, fmt.Errorf("cannot move an init function")
+			}
+			return decl, nil, nil
+
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				break
+			}
+			specs := decl.Specs
+			if !(decl.Pos() <= start && end <= decl.Pos()+token.Pos(len(decl.Tok.String()))) {
+				specs = nil
+				for _, spec := range decl.Specs {
+					var names []*ast.Ident
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						names = []*ast.Ident{spec.Name}
+					case *ast.ValueSpec:
+						names = spec.Names
+					}
+					if slices.ContainsFunc(names, func(id *ast.Ident) bool { return within(id) }) {
+						specs = []ast.Spec{spec}
+					}
+				}
+				if specs == nil {
+					break
+				}
+			}
+			// A constant of a group may take its value, or the value
+			// of iota, from its position in the group.
+			if decl.Tok == token.CONST && len(specs) < len(decl.Specs) {
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.ValueSpec)
+					if len(spec.Values) == 0 || slices.Contains(specs, ast.Spec(spec)) && mentionsIota(spec) {
+						return nil, nil, fmt.Errorf("cannot move constant %s out of its group", specs[0].(*ast.ValueSpec).Names[0].Name)
+					}
+				}
+			}
+			return decl, specs, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no package-level declaration selected")
+}
+
+// mentionsIota reports whether the values of the spec refer to iota.
+func mentionsIota(spec *ast.ValueSpec) bool {
+	found := false
+	for _, value := range spec.Values {
+		ast.Inspect(value, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && id.Name == "iota" {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// MoveDecl moves the package-level declaration at loc (see
+// [declToMove]) and, if it declares a type, the methods of that type,
+// to another package. The destination, dest, is a file, which is
+// created if it does not exist, or a directory, in which case a new
+// file is created in it. It is a URI or a file path, which is relative
+// to the directory of loc if not absolute. The destination must belong
+// to a workspace package other than the one of loc.
+//
+// MoveDecl qualifies the references to the moved symbols, both within
+// their former package and in its importers, and the references from
+// the moved declarations to the symbols of their former package. It
+// adds and removes imports as needed, and exports the symbols that
+// become referenced across the two packages. It reports an error if
+// the move would create an import cycle.
+func MoveDecl(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location, dest string) ([]protocol.DocumentChange, error) {
+	ctx, done := event.Start(ctx, "golang.MoveDecl")
+	defer done()
+
+	if strings.HasSuffix(string(loc.URI), "_test.go") {
+		return nil, fmt.Errorf("cannot move declarations of a test file")
+	}
+
+	// Type-check the widest variant of the source package,
+	// as its test files may refer to the moved declarations.
+	mps, err := snapshot.MetadataForFile(ctx, loc.URI, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(mps) == 0 {
+		return nil, fmt.Errorf("no package metadata for file %s", loc.URI)
+	}
+	srcMeta := mps[0]
+	pkgs, err := snapshot.TypeCheck(ctx, mps[len(mps)-1].ID)
+	if err != nil {
+		return nil, err
+	}
+	src := pkgs[0]
+	pgf, err := src.File(loc.URI)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := pgf.RangePos(loc.Range)
+	if err != nil {
+		return nil, err
+	}
+	decl, specs, err := declToMove(pgf, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &declMover{
+		snapshot: snapshot,
+		src:      src,
+		srcPath:  srcMeta.PkgPath,
+		objs:     make(map[types.Object]bool),
+		edits:    make(map[protocol.DocumentURI][]diff.Edit),
+	}
+	if err := m.addDecl(pgf, decl, specs); err != nil {
+		return nil, err
+	}
+
+	// Find the destination package.
+	destURI, err := moveDestination(ctx, snapshot, loc.URI, dest, m.firstName())
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(string(destURI), "_test.go") {
+		return nil, fmt.Errorf("cannot move declarations to a test file")
+	}
+	dstMeta := packageInDir(snapshot.MetadataGraph(), destURI.DirPath())
+	switch {
+	case dstMeta == nil:
+		return nil, fmt.Errorf("no package in directory %s", destURI.DirPath())
+	case dstMeta.PkgPath == srcMeta.PkgPath:
+		return nil, fmt.Errorf("%s belongs to package %s already", destURI.Path(), srcMeta.PkgPath)
+	case dstMeta.Name == "main":
+		return nil, fmt.Errorf("cannot move declarations to a main package")
+	case !snapshot.IsWorkspacePackage(dstMeta.ID):
+		return nil, fmt.Errorf("package %s is not in the workspace", dstMeta.PkgPath)
+	}
+	pkgs, err = snapshot.TypeCheck(ctx, dstMeta.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.dst, m.dstPath, m.destURI = pkgs[0], dstMeta.PkgPath, destURI
+	m.dstPGF, _ = m.dst.File(destURI)
+	if m.dstPGF == nil {
+		fh, err := snapshot.ReadFile(ctx, destURI)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fh.Content(); !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s is not a file of package %s", destURI.Path(), dstMeta.PkgPath)
+		}
+	}
+	for obj := range m.objs {
+		if m.dst.Types().Scope().Lookup(obj.Name()) != nil {
+			return nil, fmt.Errorf("package %s already declares %s", m.dst.Types().Name(), obj.Name())
+		}
+	}
+
+	if err := m.export(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.qualifyMoved(); err != nil {
+		return nil, err
+	}
+	m.qualifySource()
+	if err := m.qualifyImporters(ctx, append([]*metadata.Package{srcMeta}, mps[1:]...)); err != nil {
+		return nil, err
+	}
+	if err := m.checkImports(); err != nil {
+		return nil, err
+	}
+	return m.changes(ctx)
+}
+
+// moveDestination returns the URI of the file denoted by dest, a URI or
+// a file path relative to the directory of the file uri. If dest
+// denotes a directory, it returns the URI of a new file in it, named
+// after the symbol name.
+func moveDestination(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI, dest, name string) (protocol.DocumentURI, error) {
+	if dest == "" {
+		return "", fmt.Errorf("no destination")
+	}
+	var path string
+	if strings.HasPrefix(dest, "file:") {
+		destURI, err := protocol.ParseDocumentURI(dest)
+		if err != nil {
+			return "", err
+		}
+		path = destURI.Path()
+	} else {
+		path = filepath.FromSlash(dest)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(uri.DirPath(), path)
+		}
+	}
+	if filepath.Ext(path) == ".go" {
+		return protocol.URIFromPath(path), nil
+	}
+	fh, err := chooseNewFile(ctx, snapshot, filepath.Clean(path), name)
+	if err != nil {
+		return "", err
+	}
+	return fh.URI(), nil
+}
+
+// packageInDir returns the package whose files are in directory dir,
+// or nil if there is none. Test variants are ignored.
+func packageInDir(mg *metadata.Graph, dir string) *metadata.Package {
+	var result *metadata.Package
+	for _, mp := range mg.Packages {
+		if mp.ForTest != "" || metadata.IsCommandLineArguments(mp.ID) {
+			continue
+		}
+		if slices.ContainsFunc(mp.CompiledGoFiles, func(uri protocol.DocumentURI) bool {
+			return uri.DirPath() == dir
+		}) && (result == nil || mp.ID < result.ID) {
+			result = mp
+		}
+	}
+	return result
+}
+
+// A declMover computes the changes that move declarations from one
+// package (src) to another (dst).
+type declMover struct {
+	snapshot *cache.Snapshot
+	src      *cache.Package // widest variant of the source package
+	srcPath  metadata.PackagePath
+	dst      *cache.Package
+	dstPath  metadata.PackagePath
+	dstPGF   *parsego.File // destination file, or nil if it is new
+	destURI  protocol.DocumentURI
+
+	moved   []*movedDecl
+	objs    map[types.Object]bool // package-level objects declared by moved
+	exports map[types.Object]string
+
+	edits      map[protocol.DocumentURI][]diff.Edit // edits to existing files
+	dstImports []dstImport                          // imports of the moved declarations
+	newImports map[[2]metadata.PackagePath]bool     // import edges added by the move
+
+	// The import declarations deleted by the move, and the packages
+	// from whose files they were deleted.
+	deletedImports map[*ast.ImportSpec]bool
+	edited         []*cache.Package
+}
+
+// A movedDecl is a declaration, or a spec of a declaration, to be moved.
+type movedDecl struct {
+	pgf        *parsego.File
+	node       ast.Node // *ast.FuncDecl, *ast.GenDecl, or ast.Spec
+	start, end int      // offsets of the text of node, including comments
+	edits      []diff.Edit
+}
+
+// A dstImport is an import of the destination file.
+type dstImport struct {
+	name, path string
+	existing   bool // the file has the import already
+}
+
+// firstName returns the name of the first moved symbol.
+func (m *declMover) firstName() string {
+	switch n := m.moved[0].node.(type) {
+	case *ast.FuncDecl:
+		return n.Name.Name
+	case *ast.TypeSpec:
+		return n.Name.Name
+	case *ast.ValueSpec:
+		return n.Names[0].Name
+	case *ast.GenDecl:
+		switch spec := n.Specs[0].(type) {
+		case *ast.TypeSpec:
+			return spec.Name.Name
+		case *ast.ValueSpec:
+			return spec.Names[0].Name
+		}
+	}
+	return "decls"
+}
+
+// addDecl adds the declaration, or the specs of it, to the moved
+// declarations, along with the methods of the types it declares.
+func (m *declMover) addDecl(pgf *parsego.File, decl ast.Decl, specs []ast.Spec) error {
+	info := m.src.TypesInfo()
+	var types_ []*types.TypeName
+	addSpecs := func(specs []ast.Spec) {
+		for _, spec := range specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if tname, ok := info.Defs[spec.Name].(*types.TypeName); ok {
+					m.objs[tname] = true
+					if !spec.Assign.IsValid() {
+						types_ = append(types_, tname)
+					}
+				}
+			case *ast.ValueSpec:
+				for _, id := range spec.Names {
+					if obj := info.Defs[id]; obj != nil {
+						m.objs[obj] = true
+					}
+				}
+			}
+		}
+	}
+
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if obj := info.Defs[decl.Name]; obj != nil {
+			m.objs[obj] = true
+		}
+		if err := m.addNode(pgf, decl, decl.Doc, ""); err != nil {
+			return err
+		}
+	case *ast.GenDecl:
+		addSpecs(specs)
+		if len(specs) == len(decl.Specs) {
+			if err := m.addNode(pgf, decl, decl.Doc, ""); err != nil {
+				return err
+			}
+		} else {
+			for _, spec := range specs {
+				var doc *ast.CommentGroup
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					doc = spec.Doc
+				case *ast.ValueSpec:
+					doc = spec.Doc
+				}
+				if err := m.addNode(pgf, spec, doc, decl.Tok.String()+" "); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Move the methods of the types too.
+	for _, mpgf := range m.src.CompiledGoFiles() {
+		for _, decl := range mpgf.File.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+			method, ok := info.Defs[fn.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			_, named := typesinternal.ReceiverNamed(method.Signature().Recv())
+			if named == nil || !slices.Contains(types_, named.Obj()) {
+				continue
+			}
+			if strings.HasSuffix(string(mpgf.URI), "_test.go") {
+				return fmt.Errorf("cannot move method %s.%s, which is declared in a test file", named.Obj().Name(), method.Name())
+			}
+			if err := m.addNode(mpgf, fn, fn.Doc, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addNode adds node, with its doc comment, to the moved declarations.
+// The keyword, if any, is inserted before node when it is moved.
+func (m *declMover) addNode(pgf *parsego.File, node ast.Node, doc *ast.CommentGroup, keyword string) error {
+	pos := node.Pos()
+	if doc != nil {
+		pos = doc.Pos()
+	}
+	start, end, err := safetoken.Offsets(pgf.Tok, pos, node.End())
+	if err != nil {
+		return err
+	}
+	// Include a comment that follows node on its last line.
+	eol := len(pgf.Src)
+	if i := bytes.IndexByte(pgf.Src[end:], '\n'); i >= 0 {
+		eol = end + i
+	}
+	if rest := bytes.TrimSpace(pgf.Src[end:eol]); len(rest) == 0 || bytes.HasPrefix(rest, []byte("//")) {
+		end = eol
+	}
+	d := &movedDecl{pgf: pgf, node: node, start: start, end: end}
+	if keyword != "" {
+		offset, err := safetoken.Offset(pgf.Tok, node.Pos())
+		if err != nil {
+			return err
+		}
+		d.edits = append(d.edits, diff.Edit{Start: offset, End: offset, New: keyword})
+	}
+	m.moved = append(m.moved, d)
+	return nil
+}
+
+// movedAt returns the moved declaration that encloses pos, if any.
+func (m *declMover) movedAt(pos token.Pos) *movedDecl {
+	for _, d := range m.moved {
+		if d.pgf.File.FileStart <= pos && pos <= d.pgf.File.FileEnd {
+			if offset, err := safetoken.Offset(d.pgf.Tok, pos); err == nil && d.start <= offset && offset < d.end {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+// addEdit records an edit to the file pgf of the source package,
+// either of a moved declaration or of the file itself.
+func (m *declMover) addEdit(pgf *parsego.File, start, end token.Pos, newText string) {
+	startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, start, end)
+	if err != nil {
+		return // can't happen
+	}
+	edit := diff.Edit{Start: startOffset, End: endOffset, New: newText}
+	if d := m.movedAt(start); d != nil {
+		d.edits = append(d.edits, edit)
+	} else {
+		m.edits[pgf.URI] = append(m.edits[pgf.URI], edit)
+	}
+}
+
+// export renames the unexported symbols that will be referenced
+// across the source and destination packages: the symbols declared in
+// the moved declarations and referenced by the rest of the source
+// package, and vice versa.
+func (m *declMover) export(ctx context.Context) error {
+	info := m.src.TypesInfo()
+	m.exports = make(map[types.Object]string)
+	for _, pgf := range m.src.CompiledGoFiles() {
+		for cur := range pgf.Cursor().Preorder((*ast.Ident)(nil)) {
+			id := cur.Node().(*ast.Ident)
+			obj := info.Uses[id]
+			if obj == nil || obj.Exported() || obj.Pkg() != m.src.Types() || !isMember(obj) {
+				continue
+			}
+			if _, ok := m.exports[obj]; ok {
+				continue
+			}
+			if (m.movedAt(id.Pos()) != nil) != (m.movedAt(obj.Pos()) != nil) {
+				name, ok := exportedName(obj.Name())
+				if !ok {
+					return fmt.Errorf("cannot export %s", obj.Name())
+				}
+				m.exports[obj] = name
+			}
+		}
+	}
+
+	for obj, name := range m.exports {
+		if m.objs[obj] && m.dst.Types().Scope().Lookup(name) != nil {
+			return fmt.Errorf("cannot export %s: package %s already declares %s", obj.Name(), m.dst.Types().Name(), name)
+		}
+		loc, err := ObjectLocation(ctx, m.src.FileSet(), m.snapshot, obj)
+		if err != nil {
+			return err
+		}
+		editMap, err := renameOrdinary(ctx, m.snapshot, loc.URI, loc.Range, name)
+		if err != nil {
+			return fmt.Errorf("cannot export %s: %v", obj.Name(), err)
+		}
+		for uri, edits := range editMap {
+			pgf, err := m.src.File(uri)
+			if err != nil {
+				m.edits[uri] = append(m.edits[uri], edits...)
+				continue
+			}
+			for _, edit := range edits {
+				m.addEdit(pgf, pgf.Tok.Pos(edit.Start), pgf.Tok.Pos(edit.End), edit.New)
+			}
+		}
+	}
+	return nil
+}
+
+// isMember reports whether obj is a package-level object, a method,
+// or a field, as opposed to a local one.
+func isMember(obj types.Object) bool {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Signature().Recv() != nil || isPackageLevel(obj)
+	case *types.Var:
+		return obj.IsField() || isPackageLevel(obj)
+	}
+	return isPackageLevel(obj)
+}
+
+// isPackageLevel reports whether obj is declared at package level.
+func isPackageLevel(obj types.Object) bool {
+	return obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+}
+
+// qualifyMoved adjusts the references within the moved declarations:
+// it qualifies those to the symbols of the source package, and makes
+// unqualified those to the symbols of the destination package. It
+// records the imports that the moved declarations need.
+func (m *declMover) qualifyMoved() error {
+	info := m.src.TypesInfo()
+	for _, d := range m.moved {
+		curDecl, _ := d.pgf.Cursor().FindNode(d.node)
+		for cur := range curDecl.Preorder((*ast.Ident)(nil)) {
+			id := cur.Node().(*ast.Ident)
+			switch obj := info.Uses[id].(type) {
+			case nil:
+			case *types.PkgName:
+				sel := cur.Parent().Node().(*ast.SelectorExpr) // can't fail
+				path := obj.Imported().Path()
+				if metadata.PackagePath(path) == m.dstPath {
+					m.addEdit(d.pgf, sel.X.Pos(), sel.Sel.Pos(), "")
+					continue
+				}
+				if name := m.dstImport(obj.Name(), path); name != obj.Name() {
+					m.addEdit(d.pgf, id.Pos(), id.End(), name)
+				}
+			default:
+				if !isPackageLevel(obj) || m.objs[obj] || cur.ParentEdgeKind() == edge.SelectorExpr_Sel {
+					continue
+				}
+				switch obj.Pkg() {
+				case m.src.Types():
+					name := m.dstImport(m.src.Types().Name(), string(m.srcPath))
+					m.addEdit(d.pgf, id.Pos(), id.Pos(), name+".")
+				case m.dst.Types():
+				default:
+					return fmt.Errorf("cannot move declarations that refer to %s through a dot import", obj.Name())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dstImport returns the name by which the destination file imports the
+// package path, adding an import, with the preferred name if possible,
+// if there is none.
+func (m *declMover) dstImport(preferred, path string) string {
+	for _, imp := range m.dstImports {
+		if imp.path == path {
+			return imp.name
+		}
+	}
+	m.addImportEdge(m.dstPath, metadata.PackagePath(path))
+
+	taken := func(name string) bool {
+		if m.dst.Types().Scope().Lookup(name) != nil ||
+			slices.ContainsFunc(m.dstImports, func(imp dstImport) bool { return imp.name == name }) {
+			return true
+		}
+		if m.dstPGF != nil {
+			for _, spec := range m.dstPGF.File.Imports {
+				if pkgName := m.dst.TypesInfo().PkgNameOf(spec); pkgName != nil && pkgName.Name() == name {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if m.dstPGF != nil {
+		prefix, edits := refactor.AddImport(m.dst.TypesInfo(), m.dstPGF.File, preferred, path, "", m.dstPGF.File.FileEnd-1)
+		if len(edits) == 0 && prefix != "" {
+			name := strings.TrimSuffix(prefix, ".")
+			m.dstImports = append(m.dstImports, dstImport{name, path, true})
+			return name
+		}
+	}
+	name := preferred
+	for i := 0; taken(name); i++ {
+		name = fmt.Sprintf("%s%d", preferred, i)
+	}
+	m.dstImports = append(m.dstImports, dstImport{name, path, false})
+	return name
+}
+
+// qualifySource qualifies the references to the moved symbols from the
+// rest of the source package, and removes the imports that only the
+// moved declarations used.
+func (m *declMover) qualifySource() {
+	info := m.src.TypesInfo()
+	for _, pgf := range m.src.CompiledGoFiles() {
+		qualified := false
+		for cur := range pgf.Cursor().Preorder((*ast.Ident)(nil)) {
+			id := cur.Node().(*ast.Ident)
+			if obj := info.Uses[id]; m.objs[obj] && m.movedAt(id.Pos()) == nil {
+				prefix, edits := refactor.AddImport(info, pgf.File, m.dst.Types().Name(), string(m.dstPath), obj.Name(), id.Pos())
+				for _, edit := range edits {
+					m.addEdit(pgf, edit.Pos, edit.End, string(edit.NewText))
+				}
+				m.addEdit(pgf, id.Pos(), id.Pos(), prefix)
+				m.addImportEdge(m.srcPath, m.dstPath)
+				qualified = true
+			}
+		}
+
+		var deletes []*ast.ImportSpec
+		for _, spec := range pgf.File.Imports {
+			pkgName := info.PkgNameOf(spec)
+			if pkgName == nil || qualified && metadata.PackagePath(pkgName.Imported().Path()) == m.dstPath {
+				continue
+			}
+			used, usedOutside := false, false
+			for id, obj := range info.Uses {
+				if obj == pkgName {
+					used = true
+					if m.movedAt(id.Pos()) == nil {
+						usedOutside = true
+						break
+					}
+				}
+			}
+			if used && !usedOutside {
+				deletes = append(deletes, spec)
+			}
+		}
+		m.deleteImports(pgf, deletes)
+	}
+	m.edited = append(m.edited, m.src)
+}
+
+// diffEdit returns the deletion of a range of the file.
+func (m *declMover) diffEdit(pgf *parsego.File, rng protocol.Range) diff.Edit {
+	start, end, err := pgf.Mapper.RangeOffsets(rng)
+	if err != nil {
+		return diff.Edit{} // can't happen
+	}
+	return diff.Edit{Start: start, End: end}
+}
+
+// qualifyImporters updates the references to the moved symbols from
+// the packages that import the source package, whose variants are
+// srcs.
+func (m *declMover) qualifyImporters(ctx context.Context, srcs []*metadata.Package) error {
+	var ids []PackageID
+	for _, mp := range srcs {
+		rdeps, err := m.snapshot.ReverseDependencies(ctx, mp.ID, false)
+		if err != nil {
+			return err
+		}
+		for id, rdep := range rdeps {
+			if !rdep.IsIntermediateTestVariant() && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	slices.Sort(ids)
+	pkgs, err := m.snapshot.TypeCheck(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for obj := range m.objs {
+		names[obj.Name()] = true
+	}
+	for _, pkg := range pkgs {
+		info := pkg.TypesInfo()
+		inDst := pkg.Metadata().PkgPath == m.dstPath
+		for _, pgf := range pkg.CompiledGoFiles() {
+			rewritten := make(map[*types.PkgName]int)
+			for cur := range pgf.Cursor().Preorder((*ast.Ident)(nil)) {
+				id := cur.Node().(*ast.Ident)
+				obj := info.Uses[id]
+				if obj == nil || obj.Pkg() == nil || metadata.PackagePath(obj.Pkg().Path()) != m.srcPath || !isPackageLevel(obj) || !names[obj.Name()] {
+					continue
+				}
+				if cur.ParentEdgeKind() != edge.SelectorExpr_Sel {
+					return fmt.Errorf("cannot update the reference to %s through a dot import in %s", obj.Name(), pgf.URI.Path())
+				}
+				sel := cur.Parent().Node().(*ast.SelectorExpr)
+				pkgName, ok := info.Uses[sel.X.(*ast.Ident)].(*types.PkgName)
+				if !ok {
+					continue
+				}
+				rewritten[pkgName]++
+				prefix := ""
+				if !inDst {
+					var edits []refactor.Edit
+					prefix, edits = refactor.AddImport(info, pgf.File, m.dst.Types().Name(), string(m.dstPath), obj.Name(), sel.Pos())
+					for _, edit := range edits {
+						m.addFileEdit(pgf, edit.Pos, edit.End, string(edit.NewText))
+					}
+					m.addImportEdge(pkg.Metadata().PkgPath, m.dstPath)
+				}
+				m.addFileEdit(pgf, sel.X.Pos(), sel.Sel.Pos(), prefix)
+			}
+
+			// Remove the imports of the source package that are no longer used,
+			// unless the moved declarations need it.
+			var deletes []*ast.ImportSpec
+			for _, spec := range pgf.File.Imports {
+				pkgName := info.PkgNameOf(spec)
+				if pkgName == nil || rewritten[pkgName] == 0 || inDst && slices.ContainsFunc(m.dstImports, func(imp dstImport) bool {
+					return imp.path == string(m.srcPath)
+				}) {
+					continue
+				}
+				uses := 0
+				for _, obj := range info.Uses {
+					if obj == pkgName {
+						uses++
+					}
+				}
+				if uses == rewritten[pkgName] {
+					deletes = append(deletes, spec)
+				}
+			}
+			m.deleteImports(pgf, deletes)
+		}
+	}
+	m.edited = append(m.edited, pkgs...)
+	return nil
+}
+
+// deleteImports records the deletion of the imports of the file pgf.
+func (m *declMover) deleteImports(pgf *parsego.File, deletes []*ast.ImportSpec) {
+	for _, edit := range importDeletesEdits(pgf, deletes) {
+		m.edits[pgf.URI] = append(m.edits[pgf.URI], m.diffEdit(pgf, edit.Range))
+	}
+	for _, spec := range deletes {
+		if m.deletedImports == nil {
+			m.deletedImports = make(map[*ast.ImportSpec]bool)
+		}
+		m.deletedImports[spec] = true
+	}
+}
+
+// addFileEdit records an edit to the file pgf of another package.
+func (m *declMover) addFileEdit(pgf *parsego.File, start, end token.Pos, newText string) {
+	startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, start, end)
+	if err != nil {
+		return // can't happen
+	}
+	m.edits[pgf.URI] = append(m.edits[pgf.URI], diff.Edit{Start: startOffset, End: endOffset, New: newText})
+}
+
+// addImportEdge records that the move adds an import of package to by
+// package from.
+func (m *declMover) addImportEdge(from, to metadata.PackagePath) {
+	if m.newImports == nil {
+		m.newImports = make(map[[2]metadata.PackagePath]bool)
+	}
+	m.newImports[[2]metadata.PackagePath{from, to}] = true
+}
+
+// checkImports reports an error if the imports added by the move are
+// not allowed, or would create an import cycle in the import graph
+// that results from the move.
+func (m *declMover) checkImports() error {
+	mg := m.snapshot.MetadataGraph()
+	graph := make(map[metadata.PackagePath][]metadata.PackagePath)
+	for _, mp := range mg.Packages {
+		if mp.ForTest != "" || metadata.IsCommandLineArguments(mp.ID) {
+			continue
+		}
+		for _, id := range mp.DepsByPkgPath {
+			if dep := mg.Packages[id]; dep != nil {
+				graph[mp.PkgPath] = append(graph[mp.PkgPath], dep.PkgPath)
+			}
+		}
+	}
+
+	// Remove the edges that the move deletes: those from a package
+	// none of whose (non-test) files imports the other after the move.
+	// (The edited packages may be test variants, whose non-test
+	// files are those of the package itself.)
+	var (
+		deleted   = make(map[[2]metadata.PackagePath]bool)
+		remaining = make(map[[2]metadata.PackagePath]bool)
+	)
+	for _, pkg := range m.edited {
+		mp := pkg.Metadata()
+		for _, pgf := range pkg.CompiledGoFiles() {
+			if strings.HasSuffix(string(pgf.URI), "_test.go") {
+				continue
+			}
+			for _, spec := range pgf.File.Imports {
+				dep := mg.Packages[mp.DepsByImpPath[metadata.UnquoteImportPath(spec)]]
+				if dep == nil {
+					continue
+				}
+				edge := [2]metadata.PackagePath{mp.PkgPath, dep.PkgPath}
+				if m.deletedImports[spec] {
+					deleted[edge] = true
+				} else {
+					remaining[edge] = true
+				}
+			}
+		}
+	}
+	for edge := range deleted {
+		if !remaining[edge] {
+			graph[edge[0]] = slices.DeleteFunc(graph[edge[0]], func(p metadata.PackagePath) bool { return p == edge[1] })
+		}
+	}
+	var edges [][2]metadata.PackagePath
+	for edge := range m.newImports {
+		edges = append(edges, edge)
+	}
+	slices.SortFunc(edges, func(x, y [2]metadata.PackagePath) int {
+		return strings.Compare(string(x[0])+" "+string(x[1]), string(y[0])+" "+string(y[1]))
+	})
+	for _, edge := range edges {
+		if !slices.Contains(graph[edge[0]], edge[1]) {
+			graph[edge[0]] = append(graph[edge[0]], edge[1])
+		}
+	}
+
+	for _, edge := range edges {
+		from, to := edge[0], edge[1]
+		if !metadata.IsValidImport(from, to, m.snapshot.View().Type() != cache.GoPackagesDriverView) {
+			return fmt.Errorf("cannot move declarations to %s: package %s may not import it", to, from)
+		}
+		// Is there a path from 'to' back to 'from'?
+		parent := map[metadata.PackagePath]metadata.PackagePath{to: ""}
+		queue := []metadata.PackagePath{to}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			if p == from {
+				cycle := []string{string(from)}
+				for ; p != ""; p = parent[p] {
+					cycle = append(cycle, string(p))
+				}
+				slices.Reverse(cycle[1:])
+				return fmt.Errorf("moving the declarations to %s would create an import cycle: %s", m.dstPath, strings.Join(cycle, " -> "))
+			}
+			for _, dep := range graph[p] {
+				if _, seen := parent[dep]; !seen {
+					parent[dep] = p
+					queue = append(queue, dep)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// changes returns the document changes of the move.
+func (m *declMover) changes(ctx context.Context) ([]protocol.DocumentChange, error) {
+	// Delete the moved declarations, and compute their new text.
+	var texts []string
+	for _, d := range m.moved {
+		src := d.pgf.Src
+		text, err := diff.Apply(string(src[d.start:d.end]), shiftEdits(d.edits, -d.start))
+		if err != nil {
+			return nil, err
+		}
+		texts = append(texts, text)
+
+		// Delete whole lines, and a blank line that would otherwise follow another.
+		start, end := d.start, d.end
+		lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+		if len(bytes.TrimSpace(src[lineStart:start])) == 0 && end < len(src) && src[end] == '\n' {
+			start, end = lineStart, end+1
+			if prevBlank := start == 0 || start >= 2 && src[start-2] == '\n'; prevBlank {
+				if i := bytes.IndexByte(src[end:], '\n'); i >= 0 && len(bytes.TrimSpace(src[end:end+i])) == 0 {
+					end += i + 1
+				}
+			}
+		}
+		m.edits[d.pgf.URI] = append(m.edits[d.pgf.URI], diff.Edit{Start: start, End: end})
+	}
+	decls := strings.Join(texts, "\n\n") + "\n"
+
+	var changes []protocol.DocumentChange
+	if m.dstPGF != nil {
+		// Append the declarations to the destination file.
+		// (The file is formatted below.)
+		edits := m.edits[m.destURI]
+		for _, imp := range m.dstImports {
+			if imp.existing {
+				continue
+			}
+			name := imp.name
+			if name == importedName(m.snapshot, imp.path) {
+				name = ""
+			}
+			for _, edit := range refactor.AddImportEdits(m.dstPGF.File, name, imp.path) {
+				start, end, err := safetoken.Offsets(m.dstPGF.Tok, edit.Pos, edit.End)
+				if err != nil {
+					return nil, err
+				}
+				edits = append(edits, diff.Edit{Start: start, End: end, New: string(edit.NewText)})
+			}
+		}
+		src := m.dstPGF.Src
+		m.edits[m.destURI] = append(edits, diff.Edit{Start: len(src), End: len(src), New: "\n" + decls})
+	} else {
+		// Create the destination file.
+		var buf bytes.Buffer
+		if c := CopyrightComment(m.moved[0].pgf.File); c != nil {
+			text, err := m.moved[0].pgf.NodeText(c)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(text)
+			buf.WriteString("\n\n")
+		}
+		fmt.Fprintf(&buf, "package %s\n\n", m.dst.Types().Name())
+		for _, imp := range m.dstImports {
+			buf.WriteString("import ")
+			if imp.name != importedName(m.snapshot, imp.path) {
+				buf.WriteString(imp.name + " ")
+			}
+			buf.WriteString(strconv.Quote(imp.path) + "\n")
+		}
+		buf.WriteString("\n" + decls)
+		content, err := formatMoved(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("formatting %s: %v", m.destURI.Path(), err)
+		}
+		fh, err := m.snapshot.ReadFile(ctx, m.destURI)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes,
+			protocol.DocumentChangeCreate(m.destURI),
+			protocol.DocumentChangeEdit(fh, []protocol.TextEdit{{Range: protocol.Range{}, NewText: string(content)}}))
+	}
+
+	// Format the edited files, unless they were not formatted.
+	for uri, edits := range m.edits {
+		fh, err := m.snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		src, err := fh.Content()
+		if err != nil {
+			return nil, err
+		}
+		if formatted, err := format.Source(src); err != nil || !bytes.Equal(formatted, src) {
+			continue
+		}
+		diff.SortEdits(edits)
+		newSrc, err := diff.ApplyBytes(src, slices.Compact(edits))
+		if err != nil {
+			return nil, err
+		}
+		if formatted, err := formatMoved(newSrc); err == nil {
+			m.edits[uri] = diff.Bytes(src, formatted)
+		}
+	}
+
+	edited, err := renameEditsToDocChanges(ctx, m.snapshot, m.edits)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(edited, func(x, y protocol.DocumentChange) int {
+		return strings.Compare(string(x.TextDocumentEdit.TextDocument.URI), string(y.TextDocumentEdit.TextDocument.URI))
+	})
+	return append(edited, changes...), nil
+}
+
+// formatMoved formats Go source, merging its import declarations,
+// which the move may have added separately.
+func formatMoved(src []byte) ([]byte, error) {
+	return imports.Process("", src, &imports.Options{
+		FormatOnly: true,
+		Comments:   true,
+		TabIndent:  true,
+		TabWidth:   8,
+	})
+}
+
+// shiftEdits returns a copy of the edits with their offsets shifted by delta.
+func shiftEdits(edits []diff.Edit, delta int) []diff.Edit {
+	shifted := make([]diff.Edit, len(edits))
+	for i, edit := range edits {
+		shifted[i] = diff.Edit{Start: edit.Start + delta, End: edit.End + delta, New: edit.New}
+	}
+	diff.SortEdits(shifted)
+	return slices.Compact(shifted)
+}
+
+// importedName returns the name of the package with the given path,
+// or its last path segment if it is unknown.
+func importedName(snapshot *cache.Snapshot, path string) string {
+	for _, mp := range snapshot.MetadataGraph().ForPackagePath[metadata.PackagePath(path)] {
+		return string(mp.Name)
+	}
+	return filepath.Base(path)
+}
@@ -0,0 +1,413 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "Introduce parameter object" refactoring,
+// which replaces consecutive parameters of a function by a single
+// parameter of a new struct type.
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+
+	goastutil "golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/bug"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/internal/util/tokeninternal"
+	"golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/refactor"
+)
+
+// paramSelection describes the parameters of a function selected for
+// an "Introduce parameter object" refactoring.
+type paramSelection struct {
+	decl        *ast.FuncDecl
+	first, last int          // range [first, last) of the selected (flattened) parameters
+	names       []*ast.Ident // names of the selected parameters
+	types       []ast.Expr   // types of the selected parameters
+}
+
+// selectParams returns the consecutive parameters of a function
+// declaration spanned by the selection [start, end).
+//
+// At least minParams parameters must be selected. They must all be named,
+// and none may be variadic. The function must have a body, and may not
+// be generic.
+func selectParams(pgf *parsego.File, start, end token.Pos, minParams int) (*paramSelection, error) {
+	path, _ := goastutil.PathEnclosingInterval(pgf.File, start, end)
+	var decl *ast.FuncDecl
+	for _, n := range path {
+		if n, ok := n.(*ast.FuncDecl); ok {
+			decl = n
+			break
+		}
+	}
+	if decl == nil || !(decl.Type.Params.Opening < start && end <= decl.Type.Params.Closing) {
+		return nil, fmt.Errorf("selection is not within a parameter list")
+	}
+	if decl.Body == nil {
+		return nil, fmt.Errorf("function has no body")
+	}
+	if decl.Type.TypeParams != nil || decl.Recv != nil && isGenericRecv(decl.Recv.List[0].Type) {
+		return nil, fmt.Errorf("generic functions are not supported")
+	}
+
+	sel := &paramSelection{decl: decl, first: -1}
+	i := 0
+	for id, field := range astutil.FlatFields(decl.Type.Params) {
+		// A parameter is selected if the selection intersects its name,
+		// or, if it has none, its type.
+		var n ast.Node = field.Type
+		if id != nil {
+			n = id
+		}
+		if n.Pos() < end && start < n.End() {
+			if id == nil || id.Name == "_" {
+				return nil, fmt.Errorf("selected parameters must be named")
+			}
+			if is[*ast.Ellipsis](field.Type) {
+				return nil, fmt.Errorf("variadic parameters are not supported")
+			}
+			if sel.first < 0 {
+				sel.first = i
+			}
+			sel.last = i + 1
+			sel.names = append(sel.names, id)
+			sel.types = append(sel.types, field.Type)
+		}
+		i++
+	}
+	if len(sel.names) < minParams {
+		if minParams == 1 {
+			return nil, fmt.Errorf("selection must span a parameter")
+		}
+		return nil, fmt.Errorf("selection must span at least %d parameters", minParams)
+	}
+	return sel, nil
+}
+
+// isGenericRecv reports whether the receiver type expression recv
+// declares type parameters.
+func isGenericRecv(recv ast.Expr) bool {
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch recv.(type) {
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	}
+	return false
+}
+
+// IntroduceParamObject computes a refactoring that replaces the
+// consecutive parameters of the function declaration selected by rng
+// with a single parameter of a new struct type, whose fields are the
+// selected parameters. All calls to the function are rewritten to
+// construct a value of the new type.
+//
+// For example, given the selection of b and c in
+//
+//	func Foo(a, b, c int) int {
+//		return a + b*c
+//	}
+//
+// the declaration becomes
+//
+//	// FooParams holds the parameters of Foo.
+//	type FooParams struct {
+//		B int
+//		C int
+//	}
+//
+//	func Foo(a int, params FooParams) int {
+//		return a + params.B*params.C
+//	}
+//
+// and a call Foo(1, 2, 3) becomes Foo(1, FooParams{B: 2, C: 3}).
+//
+// The new type and its fields are exported if the function is, so that
+// calls in other packages may construct it.
+//
+// Like [ChangeSignature], this rewrite works by inlining calls to a
+// wrapper of the new declaration.
+func IntroduceParamObject(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range) ([]protocol.DocumentChange, error) {
+	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
+		var sample string
+		if len(perrors) > 0 {
+			sample = perrors[0].Error()
+		} else {
+			sample = terrors[0].Error()
+		}
+		return nil, fmt.Errorf("can't change signatures for packages with parse or type errors: (e.g. %s)", sample)
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := selectParams(pgf, start, end, 2)
+	if err != nil {
+		return nil, err
+	}
+	decl := sel.decl
+	fset := tokeninternal.FileSetFor(pgf.Tok)
+
+	// Choose the names of the new type, of its fields, and of the new
+	// parameter.
+	exported := decl.Name.IsExported()
+	typeName := decl.Name.Name + "Params"
+	typeName = refactor.FreshName(pkg.TypesInfo().Scopes[pgf.File], token.NoPos, typeName)
+	fields := make([]string, len(sel.names))
+	{
+		seen := make(map[string]bool)
+		for i, id := range sel.names {
+			name := id.Name
+			if exported {
+				name, _ = exportedName(name)
+				if !token.IsExported(name) {
+					return nil, fmt.Errorf("cannot export a field for parameter %s", id.Name)
+				}
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("parameters %s conflict as fields", name)
+			}
+			seen[name] = true
+			fields[i] = name
+		}
+	}
+	paramName := "params"
+	{
+		// Avoid any name in the declaration, so that the new parameter
+		// neither shadows nor is shadowed by another declaration.
+		used := make(map[string]bool)
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+			return true
+		})
+		for i := 0; used[paramName]; i++ {
+			paramName = fmt.Sprintf("params%d", i)
+		}
+	}
+
+	var typeDecl strings.Builder
+	fmt.Fprintf(&typeDecl, "// %s holds the parameters of %s.\n", typeName, decl.Name.Name)
+	fmt.Fprintf(&typeDecl, "type %s struct {\n", typeName)
+	for i, name := range fields {
+		fmt.Fprintf(&typeDecl, "\t%s %s\n", name, FormatNode(fset, sel.types[i]))
+	}
+	typeDecl.WriteString("}\n")
+
+	// Step 1: create the new declaration, in which the selected
+	// parameters are replaced by the new one, and references to them
+	// by field selections.
+	newDecl := astutil.CloneNode(decl)
+	newDecl.Type.Params = groupParams(decl.Type.Params, sel, paramName, typeName)
+	{
+		selected := make(map[types.Object]string) // maps selected parameter to field name
+		for i, id := range sel.names {
+			selected[pkg.TypesInfo().Defs[id]] = fields[i]
+		}
+		refs := make(map[token.Pos]string) // maps position of each reference to field name
+		for id, obj := range pkg.TypesInfo().Uses {
+			if field, ok := selected[obj]; ok && decl.Body.Pos() <= id.Pos() && id.Pos() < decl.Body.End() {
+				refs[id.Pos()] = field
+			}
+		}
+		goastutil.Apply(newDecl.Body, func(c *goastutil.Cursor) bool {
+			if id, ok := c.Node().(*ast.Ident); ok {
+				if field, ok := refs[id.Pos()]; ok {
+					c.Replace(&ast.SelectorExpr{
+						X:   &ast.Ident{NamePos: id.NamePos, Name: paramName},
+						Sel: &ast.Ident{NamePos: id.NamePos, Name: field},
+					})
+				}
+			}
+			return true
+		}, nil)
+	}
+
+	// Step 2: build a wrapper function calling the new declaration with
+	// a composite literal of the new type.
+	params, names, variadic := delegatingParams(decl, func(int) bool { return true })
+	lit := &ast.CompositeLit{Type: ast.NewIdent(typeName)}
+	for i, field := range fields {
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{
+			Key:   ast.NewIdent(field),
+			Value: ast.NewIdent(names[sel.first+i]),
+		})
+	}
+	var args []ast.Expr
+	for _, name := range names[:sel.first] {
+		args = append(args, ast.NewIdent(name))
+	}
+	args = append(args, lit)
+	for _, name := range names[sel.last:] {
+		args = append(args, ast.NewIdent(name))
+	}
+
+	// Step 3: rewrite all referring calls, by swapping in the wrapper and
+	// inlining all.
+	newContent, err := rewriteCalls(ctx, signatureRewrite{
+		snapshot: snapshot,
+		pkg:      pkg,
+		pgf:      pgf,
+		origDecl: decl,
+		newDecl:  newDecl,
+		params:   params,
+		callArgs: args,
+		variadic: variadic,
+		decls:    typeDecl.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Finally, rewrite the original declaration, and declare the new
+	// type before it.
+	{
+		idx := findDecl(pgf.File, decl)
+		if idx < 0 {
+			return nil, bug.Errorf("didn't find original decl")
+		}
+		src, ok := newContent[pgf.URI]
+		if !ok {
+			src = pgf.Src
+		}
+		logf := logger(ctx, "introduce parameter object", snapshot.Options().VerboseOutput)
+		src, err := rewriteParamRefs(logf, pkg, pgf.URI, idx, src, sel, paramName, fields, typeDecl.String())
+		if err != nil {
+			return nil, err
+		}
+		// The new type declaration precedes the original one.
+		src, err = rewriteSignature(fset, idx+1, src, newDecl)
+		if err != nil {
+			return nil, err
+		}
+		if formatted, err := format.Source(src); err == nil {
+			src = formatted
+		}
+		newContent[pgf.URI] = src
+	}
+
+	return documentChanges(ctx, snapshot, newContent)
+}
+
+// groupParams returns a copy of the parameter list params in which
+// the selected parameters are replaced by a single parameter
+// of the named type.
+func groupParams(params *ast.FieldList, sel *paramSelection, name, typeName string) *ast.FieldList {
+	list := &ast.FieldList{}
+	i := 0 // index of (flattened) parameter
+	for _, field := range params.List {
+		if len(field.Names) == 0 {
+			list.List = append(list.List, astutil.CloneNode(field))
+			i++
+			continue
+		}
+		// Split the names of the field into those before, within,
+		// and after the selection.
+		var before, after []*ast.Ident
+		for _, id := range field.Names {
+			switch {
+			case i < sel.first:
+				before = append(before, ast.NewIdent(id.Name))
+			case i >= sel.last:
+				after = append(after, ast.NewIdent(id.Name))
+			}
+			if i == sel.first {
+				if len(before) > 0 {
+					list.List = append(list.List, &ast.Field{Names: before, Type: astutil.CloneNode(field.Type)})
+					before = nil
+				}
+				list.List = append(list.List, &ast.Field{
+					Names: []*ast.Ident{ast.NewIdent(name)},
+					Type:  ast.NewIdent(typeName),
+				})
+			}
+			i++
+		}
+		for _, names := range [][]*ast.Ident{before, after} {
+			if len(names) > 0 {
+				list.List = append(list.List, &ast.Field{Names: names, Type: astutil.CloneNode(field.Type)})
+			}
+		}
+	}
+	return list
+}
+
+// rewriteParamRefs rewrites the references to the selected parameters
+// within the body of the idx'th declaration of the content src of the
+// declaring file, as selections of the named fields of the new
+// parameter, and inserts the declaration typeDecl before it.
+//
+// The file is type-checked anew, as the inlining of recursive calls
+// may have changed the body of the declaration. Type errors are
+// expected, as the calls of the function no longer match its
+// signature.
+func rewriteParamRefs(logf func(string, ...any), pkg *cache.Package, uri protocol.DocumentURI, idx int, src []byte, sel *paramSelection, paramName string, fields []string, typeDecl string) ([]byte, error) {
+	fset := pkg.FileSet()
+	file, err := parser.ParseFile(fset, uri.Path(), src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, bug.Errorf("re-parsing declaring file failed: %v", err)
+	}
+	decl, _ := file.Decls[idx].(*ast.FuncDecl)
+	if decl == nil || decl.Name.Name != sel.decl.Name.Name {
+		return nil, bug.Errorf("inlining affected declaration order: found %v, not func %s", decl, sel.decl.Name.Name)
+	}
+	_, info, err := reTypeCheck(logf, pkg, map[protocol.DocumentURI]*ast.File{uri: file}, true)
+	if err != nil {
+		return nil, err
+	}
+	tok := fset.File(file.FileStart)
+
+	selected := make(map[types.Object]string) // maps selected parameter to field name
+	i := 0
+	for id := range astutil.FlatFields(decl.Type.Params) {
+		if sel.first <= i && i < sel.last {
+			selected[info.Defs[id]] = fields[i-sel.first]
+		}
+		i++
+	}
+	var edits []diff.Edit
+	var inspectErr error
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if field, ok := selected[info.Uses[id]]; ok {
+				start, end, err := safetoken.Offsets(tok, id.Pos(), id.End())
+				if err != nil {
+					inspectErr = err
+					return false
+				}
+				edits = append(edits, diff.Edit{Start: start, End: end, New: paramName + "." + field})
+			}
+		}
+		return inspectErr == nil
+	})
+	if inspectErr != nil {
+		return nil, inspectErr
+	}
+
+	pos := decl.Pos()
+	if decl.Doc != nil {
+		pos = decl.Doc.Pos()
+	}
+	offset, err := safetoken.Offset(tok, pos)
+	if err != nil {
+		return nil, err
+	}
+	edits = append(edits, diff.Edit{Start: offset, End: offset, New: typeDecl + "\n"})
+	return diff.ApplyBytes(src, edits)
+}
@@ -469,47 +469,7 @@ func Rename(ctx context.Context, snapshot *cache.Snapshot, f file.Handle, rng pr
 		}
 	}
 
-	// Convert edits to protocol form.
-	result := make(map[protocol.DocumentURI][]protocol.TextEdit)
-	for uri, edits := range editMap {
-		// Sort and de-duplicate edits.
-		//
-		// Overlapping edits may arise in local renamings (due
-		// to type switch implicits) and globals ones (due to
-		// processing multiple package variants).
-		//
-		// We assume renaming produces diffs that are all
-		// replacements (no adjacent insertions that might
-		// become reordered) and that are either identical or
-		// non-overlapping.
-		diff.SortEdits(edits)
-		edits = slices.Compact(edits)
-
-		// TODO(adonovan): the logic above handles repeat edits to the
-		// same file URI (e.g. as a member of package p and p_test) but
-		// is not sufficient to handle file-system level aliasing arising
-		// from symbolic or hard links. For that, we should use a
-		// robustio-FileID-keyed map.
-		// See https://go.dev/cl/457615 for example.
-		// This really occurs in practice, e.g. kubernetes has
-		// vendor/k8s.io/kubectl -> ../../staging/src/k8s.io/kubectl.
-		fh, err := snapshot.ReadFile(ctx, uri)
-		if err != nil {
-			return nil, err
-		}
-		data, err := fh.Content()
-		if err != nil {
-			return nil, err
-		}
-		m := protocol.NewMapper(uri, data)
-		textedits, err := protocol.EditsFromDiffEdits(m, edits)
-		if err != nil {
-			return nil, err
-		}
-		result[uri] = textedits
-	}
-
-	changes, err := editsToDocChanges(ctx, snapshot, result)
+	changes, err := renameEditsToDocChanges(ctx, snapshot, editMap)
 	if err != nil {
 		return nil, err
 	}
@@ -560,6 +520,51 @@ func Rename(ctx context.Context, snapshot *cache.Snapshot, f file.Handle, rng pr
 	return changes, nil
 }
 
+// renameEditsToDocChanges converts the edits computed by a renaming
+// operation to protocol form.
+func renameEditsToDocChanges(ctx context.Context, snapshot *cache.Snapshot, editMap map[protocol.DocumentURI][]diff.Edit) ([]protocol.DocumentChange, error) {
+	result := make(map[protocol.DocumentURI][]protocol.TextEdit)
+	for uri, edits := range editMap {
+		// Sort and de-duplicate edits.
+		//
+		// Overlapping edits may arise in local renamings (due
+		// to type switch implicits) and globals ones (due to
+		// processing multiple package variants).
+		//
+		// We assume renaming produces diffs that are all
+		// replacements (no adjacent insertions that might
+		// become reordered) and that are either identical or
+		// non-overlapping.
+		diff.SortEdits(edits)
+		edits = slices.Compact(edits)
+
+		// TODO(adonovan): the logic above handles repeat edits to the
+		// same file URI (e.g. as a member of package p and p_test) but
+		// is not sufficient to handle file-system level aliasing arising
+		// from symbolic or hard links. For that, we should use a
+		// robustio-FileID-keyed map.
+		// See https://go.dev/cl/457615 for example.
+		// This really occurs in practice, e.g. kubernetes has
+		// vendor/k8s.io/kubectl -> ../../staging/src/k8s.io/kubectl.
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		data, err := fh.Content()
+		if err != nil {
+			return nil, err
+		}
+		m := protocol.NewMapper(uri, data)
+		textedits, err := protocol.EditsFromDiffEdits(m, edits)
+		if err != nil {
+			return nil, err
+		}
+		result[uri] = textedits
+	}
+
+	return editsToDocChanges(ctx, snapshot, result)
+}
+
 // renameOrdinary renames an ordinary (non-package) name throughout the workspace.
 func renameOrdinary(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI, rng protocol.Range, newName string) (map[protocol.DocumentURI][]diff.Edit, error) {
 	// Type-check the referring package and locate the object(s).
@@ -1079,7 +1084,17 @@ func updatePackageDeclsAndImports(ctx context.Context, s *cache.Snapshot, f file
 	if modulePath == oldPkgPath {
 		return fmt.Errorf("cannot rename package: module path %q is the same as the package path, so renaming the package directory would have no effect", modulePath)
 	}
+	return updatePackagePaths(ctx, s, modulePath, oldPkgPath, newPkgPath, newName, renamingEdits, moveSubpackages)
+}
 
+// updatePackagePaths computes the edits to package clauses and import
+// declarations required to change the path of the package oldPkgPath
+// (which need not exist) of module modulePath to newPkgPath, and the
+// name of that package, if any, to newName.
+//
+// If moveSubpackages is set, the paths of all packages of the same module
+// beneath oldPkgPath are changed too.
+func updatePackagePaths(ctx context.Context, s *cache.Snapshot, modulePath, oldPkgPath, newPkgPath PackagePath, newName PackageName, renamingEdits map[protocol.DocumentURI][]diff.Edit, moveSubpackages bool) error {
 	// We must inspect all packages, not just direct importers,
 	// because we might also rename subpackages, which may be unrelated.
 	// (If the renamed package imports a subpackage it may require
@@ -1094,8 +1109,10 @@ func updatePackageDeclsAndImports(ctx context.Context, s *cache.Snapshot, f file
 		// package path as a dir prefix, but still need their package clauses
 		// renamed.
 		if mp.PkgPath == oldPkgPath+"_test" {
-			if err := renamePackageClause(ctx, mp, s, newName+"_test", renamingEdits); err != nil {
-				return err
+			if mp.Name != newName+"_test" {
+				if err := renamePackageClause(ctx, mp, s, newName+"_test", renamingEdits); err != nil {
+					return err
+				}
 			}
 			continue
 		}
@@ -1122,7 +1139,7 @@ func updatePackageDeclsAndImports(ctx context.Context, s *cache.Snapshot, f file
 		newImportPath := string(newPkgPath) + suffix
 
 		pkgName := mp.Name
-		if mp.PkgPath == oldPkgPath {
+		if mp.PkgPath == oldPkgPath && mp.Name != newName {
 			pkgName = newName
 
 			if err := renamePackageClause(ctx, mp, s, newName, renamingEdits); err != nil {
@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the edits that accompany the renaming of files and
+// directories by the client (workspace/willRenameFiles).
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/pathutil"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/event"
+)
+
+// RenameFiles returns the edits that must accompany the given renamings
+// of files and directories, as announced by a workspace/willRenameFiles
+// request. The edits apply to the files at their original locations,
+// since the client applies them before it renames the files.
+//
+// When a directory is moved within its module, the import paths of the
+// packages within it change: every import of them is updated, and if
+// the name of the package in the directory itself matched its directory
+// name, its package clause (and the references to it in importing
+// files) are changed to match the new directory name.
+//
+// When a Go file is moved into the directory of a different package,
+// its package clause is changed to that of the destination package.
+func RenameFiles(ctx context.Context, snapshot *cache.Snapshot, renames []protocol.FileRename) ([]protocol.DocumentChange, error) {
+	ctx, done := event.Start(ctx, "golang.RenameFiles")
+	defer done()
+
+	editMap := make(map[protocol.DocumentURI][]diff.Edit)
+	for _, r := range renames {
+		oldPath, newPath := r.OldURI.Path(), r.NewURI.Path()
+		var err error
+		if filepath.Ext(oldPath) == ".go" {
+			if filepath.Dir(oldPath) != filepath.Dir(newPath) {
+				err = moveGoFile(ctx, snapshot, r.OldURI, filepath.Dir(newPath), editMap)
+			}
+		} else {
+			// Not a Go file: assume it is a directory.
+			err = moveDir(ctx, snapshot, oldPath, newPath, editMap)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return renameEditsToDocChanges(ctx, snapshot, editMap)
+}
+
+// moveGoFile computes the edit to the package clause of the Go file uri
+// required by its move into directory newDir.
+func moveGoFile(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI, newDir string, editMap map[protocol.DocumentURI][]diff.Edit) error {
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return err
+	}
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Header)
+	if err != nil {
+		return err
+	}
+	if pgf.File.Name == nil {
+		return nil // no package declaration
+	}
+	oldName := pgf.File.Name.Name
+
+	// Find the name of the package in the destination directory.
+	// An external test file keeps the _test suffix.
+	allMetadata, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	var newName PackageName
+	for _, mp := range allMetadata {
+		if mp.ForTest != "" || metadata.IsCommandLineArguments(mp.ID) || len(mp.CompiledGoFiles) == 0 {
+			continue
+		}
+		if mp.CompiledGoFiles[0].DirPath() == newDir {
+			newName = mp.Name
+			break
+		}
+	}
+	if newName == "" {
+		return nil // destination package unknown
+	}
+	if strings.HasSuffix(oldName, "_test") && strings.HasSuffix(fh.URI().Path(), "_test.go") {
+		newName += "_test"
+	}
+	if string(newName) == oldName {
+		return nil
+	}
+	edit, err := posEdit(pgf.Tok, pgf.File.Name.Pos(), pgf.File.Name.End(), string(newName))
+	if err != nil {
+		return err
+	}
+	editMap[uri] = append(editMap[uri], edit)
+	return nil
+}
+
+// moveDir computes the edits to package clauses, imports, and go.mod
+// replace directives required by the move of directory oldDir to newDir.
+func moveDir(ctx context.Context, snapshot *cache.Snapshot, oldDir, newDir string, editMap map[protocol.DocumentURI][]diff.Edit) error {
+	allMetadata, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Find the module that encloses the directory, and the package
+	// in the directory itself, if any. Packages of modules rooted at
+	// or beneath oldDir are unaffected: a module moves with its root
+	// directory, and its import paths do not change.
+	var (
+		module  *metadata.Package // a package in oldDir or beneath, of the enclosing module
+		pkgName PackageName       // the name of the package in oldDir, if any
+	)
+	for _, mp := range allMetadata {
+		if metadata.IsCommandLineArguments(mp.ID) || len(mp.CompiledGoFiles) == 0 || mp.Module == nil {
+			continue
+		}
+		dir := mp.CompiledGoFiles[0].DirPath()
+		if !pathutil.InDir(oldDir, dir) || pathutil.InDir(oldDir, mp.Module.Dir) {
+			continue
+		}
+		if module == nil || len(mp.Module.Dir) > len(module.Module.Dir) {
+			// Prefer the innermost enclosing module.
+			module = mp
+		}
+		if dir == oldDir && mp.ForTest == "" {
+			pkgName = PackageName(strings.TrimSuffix(string(mp.Name), "_test"))
+		}
+	}
+	if module == nil {
+		return nil // no packages affected
+	}
+	modDir := module.Module.Dir
+	if !pathutil.InDir(modDir, newDir) {
+		return fmt.Errorf("cannot move packages out of module %s", module.Module.Path)
+	}
+
+	// Compute the old and new import paths corresponding to the directories.
+	importPath := func(dir string) (PackagePath, error) {
+		rel, err := filepath.Rel(modDir, dir)
+		if err != nil {
+			return "", err
+		}
+		return PackagePath(path.Join(module.Module.Path, filepath.ToSlash(rel))), nil
+	}
+	oldPkgPath, err := importPath(oldDir)
+	if err != nil {
+		return err
+	}
+	newPkgPath, err := importPath(newDir)
+	if err != nil {
+		return err
+	}
+
+	// Follow the convention that a package's name matches its directory:
+	// if it did before the move, it should afterwards.
+	newName := pkgName
+	if base := filepath.Base(newDir); string(pkgName) == filepath.Base(oldDir) && pkgName != "main" && isValidIdentifier(base) {
+		newName = PackageName(base)
+	}
+
+	if err := updatePackagePaths(ctx, snapshot, PackagePath(module.Module.Path), oldPkgPath, newPkgPath, newName, editMap, true); err != nil {
+		return err
+	}
+	return updateModFiles(ctx, snapshot, oldDir, newDir, editMap, true)
+}
@@ -115,6 +115,10 @@ func ResolveCommand(ctx context.Context, params *protocol.ExecuteCommandParams,
 		if err := resolveImplementInterface(options, params); err != nil {
 			return nil, err
 		}
+	case "gopls.move_decl":
+		if err := resolveMoveDecl(options, params); err != nil {
+			return nil, err
+		}
 	}
 	return params, nil
 }
@@ -285,6 +289,71 @@ func resolveImplementInterface(options settings.ClientOptions, param *protocol.E
 	return nil
 }
 
+var moveDeclFormFile = []protocol.FormField{
+	{
+		ID:          "destination",
+		Description: "file or directory of the package to move the declaration to",
+		Type: protocol.FormFieldTypeFile{
+			Kind:      "file",
+			Existence: protocol.FileExistenceNew | protocol.FileExistenceExisting,
+			Type:      protocol.FileTypeRegular | protocol.FileTypeDirectory,
+		},
+		Required: true,
+	},
+}
+
+var moveDeclFormString = []protocol.FormField{
+	{
+		ID:          "destination",
+		Description: `file or directory of the package to move the declaration to, relative to the current file's directory; e.g., "../util"`,
+		Type: protocol.FormFieldTypeString{
+			Kind: "string",
+		},
+		Required: true,
+	},
+}
+
+func resolveMoveDecl(options settings.ClientOptions, param *protocol.ExecuteCommandParams) error {
+	var a0 command.MoveDeclArgs
+	if err := command.UnmarshalArgs(param.Arguments, &a0); err != nil {
+		return err
+	}
+	if a0.Dest != "" {
+		return nil // no dialog needed
+	}
+
+	var form []protocol.FormField
+	if ok := options.SupportedInteractiveInputTypes[settings.InteractiveInputTypeFile]; ok {
+		form = moveDeclFormFile
+	} else if ok := options.SupportedInteractiveInputTypes[settings.InteractiveInputTypeString]; ok {
+		form = moveDeclFormString
+	} else {
+		// This should not happen, as the gopls should not offer such code
+		// action if the language client does not support any kind above.
+		return fmt.Errorf("internal error: unsupported interactive input types: %v", options.SupportedInteractiveInputTypes)
+	}
+
+	// First call, return the empty form.
+	if len(param.FormAnswers) == 0 {
+		param.FormFields = form
+		return nil
+	}
+
+	v, err := FormAnswer[string](&param.InteractiveParams, "destination")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(v) == "" {
+		form := slices.Clone(form)
+		form[0].Error = "destination must not be empty"
+		param.FormFields = form
+		return nil
+	}
+
+	param.FormFields = nil
+	return nil
+}
+
 // FormAnswer finds, validates, and returns the unique answer for id.
 //
 // It uses a linear scan since the number of answers is small (usually < 5).
@@ -13,14 +13,19 @@ import (
 	"strings"
 	"sync"
 
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/objectpath"
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/metadata"
 	"golang.org/x/tools/gopls/internal/cache/methodsets"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/moreiters"
+	"golang.org/x/tools/internal/typeparams"
 )
 
-// Type hierarchy support (using method sets)
+// Type hierarchy support (using method sets, and for generics, using
+// instantiations and constraint satisfaction)
 //
 // TODO(adonovan):
 // - Support type hierarchy by signatures (using Kind=Function).
@@ -68,9 +73,14 @@ func PrepareTypeHierarchy(ctx context.Context, snapshot *cache.Snapshot, fh file
 		pkgpath = tname.Pkg().Path()
 	}
 
+	kind := cond(types.IsInterface(tname.Type()), protocol.Interface, protocol.Class)
+	if is[*types.TypeParam](tname.Type()) {
+		kind = protocol.TypeParameter
+	}
+
 	return []protocol.TypeHierarchyItem{{
 		Name:           tname.Name(),
-		Kind:           cond(types.IsInterface(tname.Type()), protocol.Interface, protocol.Class),
+		Kind:           kind,
 		Detail:         pkgpath,
 		URI:            declLoc.URI,
 		Range:          declLoc.Range, // (in theory this should be the entire declaration)
@@ -104,7 +114,7 @@ func relatedTypes(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 		itemsMu sync.Mutex
 		items   []protocol.TypeHierarchyItem
 	)
-	err = implementationsMsets(ctx, snapshot, pkg, cur, rel, func(pkgpath metadata.PackagePath, name string, abstract bool, loc protocol.Location) {
+	yield := func(pkgpath metadata.PackagePath, name string, abstract bool, loc protocol.Location) {
 		if pkgpath == "" {
 			pkgpath = "builtin"
 		}
@@ -119,10 +129,16 @@ func relatedTypes(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 			Range:          loc.Range, // (in theory this should be the entire declaration)
 			SelectionRange: loc.Range,
 		})
-	})
+	}
+	isTypeParam, err := genericRelatedTypes(ctx, snapshot, pkg, cur, rel, yield)
 	if err != nil {
 		return nil, err
 	}
+	if !isTypeParam { // type parameters have no method sets of their own
+		if err := implementationsMsets(ctx, snapshot, pkg, cur, rel, yield); err != nil {
+			return nil, err
+		}
+	}
 
 	// Sort by (package, name, URI, range) then
 	// de-duplicate based on the same 4-tuple
@@ -148,3 +164,246 @@ func relatedTypes(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 
 	return items, nil
 }
+
+// genericRelatedTypes reports the types related to the generic type,
+// type parameter, or instantiation at the cursor, in addition to those
+// related to it by method sets:
+//
+//   - the subtypes of a package-level generic type are its
+//     instantiations in the workspace, and the supertype of an
+//     instantiation is its generic type;
+//   - the subtypes of a type parameter are the package-level types (and
+//     pointers to them) in the workspace that satisfy its constraint,
+//     and its supertype is its constraint, if named.
+//
+// It reports whether the cursor denotes a type parameter.
+func genericRelatedTypes(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, cur inspector.Cursor, rel methodsets.TypeRelation, yield implYieldFunc) (bool, error) {
+	id, ok := cur.Node().(*ast.Ident)
+	if !ok {
+		return false, nil
+	}
+	info := pkg.TypesInfo()
+
+	// Instantiation (at a reference, such as an item produced below)?
+	if inst, ok := info.Instances[id]; ok && rel&methodsets.Supertype != 0 {
+		if named, ok := inst.Type.(*types.Named); ok {
+			obj := named.Origin().Obj()
+			loc, err := ObjectLocation(ctx, pkg.FileSet(), snapshot, obj)
+			if err != nil {
+				return false, err
+			}
+			yield(PackagePath(obj.Pkg().Path()), obj.Name(), types.IsInterface(named), loc)
+		}
+		return false, nil
+	}
+
+	tname, ok := info.ObjectOf(id).(*types.TypeName)
+	if !ok || tname.Pkg() == nil {
+		return false, nil
+	}
+	switch t := tname.Type().(type) {
+	case *types.Named:
+		if t.TypeParams().Len() > 0 && tname.Parent() == tname.Pkg().Scope() && rel&methodsets.Subtype != 0 {
+			return false, genericInstances(ctx, snapshot, pkg, tname, yield)
+		}
+
+	case *types.TypeParam:
+		if rel&methodsets.Supertype != 0 {
+			if constraint, ok := types.Unalias(t.Constraint()).(*types.Named); ok {
+				obj := constraint.Obj()
+				loc, err := ObjectLocation(ctx, pkg.FileSet(), snapshot, obj)
+				if err != nil {
+					return true, err
+				}
+				var pkgpath PackagePath
+				if obj.Pkg() != nil {
+					pkgpath = PackagePath(obj.Pkg().Path())
+				}
+				yield(pkgpath, obj.Name(), true, loc)
+			}
+		}
+		if rel&methodsets.Subtype != 0 {
+			return true, constraintSatisfiers(ctx, snapshot, pkg, tname, yield)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// genericInstances reports each distinct instantiation of the
+// package-level generic type tname in the workspace packages that
+// depend on its package, at its first reference in each package.
+func genericInstances(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, tname *types.TypeName, yield implYieldFunc) error {
+	declURI := protocol.URIFromPath(pkg.FileSet().File(tname.Pos()).Name())
+	pkgs, err := typeCheckReverseDependencies(ctx, snapshot, declURI, true)
+	if err != nil {
+		return err
+	}
+	// Instantiations are named relative to the generic type's package.
+	qual := func(p *types.Package) string {
+		if p.Path() == tname.Pkg().Path() {
+			return ""
+		}
+		return p.Name()
+	}
+	for _, pkg := range pkgs {
+		if !snapshot.IsWorkspacePackage(pkg.Metadata().ID) {
+			continue
+		}
+		first := make(map[string]*ast.Ident) // first reference to each instantiation
+		var free typeparams.Free
+		for id, inst := range pkg.TypesInfo().Instances {
+			named, ok := inst.Type.(*types.Named)
+			if !ok {
+				continue // instantiated function
+			}
+			obj := named.Origin().Obj()
+			if obj.Pkg() == nil || obj.Pkg().Path() != tname.Pkg().Path() || obj.Name() != tname.Name() || obj.Parent() != obj.Pkg().Scope() {
+				continue
+			}
+			// Skip instantiations by type parameters, such as the
+			// receiver List[T] of a method, or a field of type
+			// *List[T] within the declaration of List.
+			if moreiters.Any(inst.TypeArgs.Types(), free.Has) {
+				continue
+			}
+			name := types.TypeString(named, qual)
+			if prev, ok := first[name]; !ok || id.Pos() < prev.Pos() {
+				first[name] = id
+			}
+		}
+		for name, id := range first {
+			pgf, err := pkg.FileEnclosing(id.Pos())
+			if err != nil {
+				return err
+			}
+			loc, err := pgf.NodeLocation(id)
+			if err != nil {
+				return err
+			}
+			yield(pkg.Metadata().PkgPath, name, types.IsInterface(tname.Type()), loc)
+		}
+	}
+	return nil
+}
+
+// constraintSatisfiers reports the package-level types, and pointers
+// to them, that satisfy the constraint of the type parameter tname:
+// those declared in the workspace packages that depend on the package
+// declaring tname, or exported by the workspace packages they import,
+// since either may be a type argument in the dependent package; and
+// the predeclared types.
+//
+// A constraint satisfied by all types, such as any, yields no results.
+func constraintSatisfiers(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, tname *types.TypeName, yield implYieldFunc) error {
+	iface, ok := tname.Type().Underlying().(*types.Interface)
+	if !ok || iface.IsMethodSet() && iface.NumMethods() == 0 {
+		return nil // (No point reporting that every type satisfies 'any'.)
+	}
+
+	// A constraint with a type set may be satisfied by predeclared types.
+	if !iface.IsMethodSet() {
+		for _, name := range types.Universe.Names() {
+			obj, ok := types.Universe.Lookup(name).(*types.TypeName)
+			if !ok || types.IsInterface(obj.Type()) || !types.Satisfies(obj.Type(), iface) {
+				continue
+			}
+			loc, err := ObjectLocation(ctx, pkg.FileSet(), snapshot, obj)
+			if err != nil {
+				return err
+			}
+			yield("", name, false, loc)
+		}
+	}
+
+	// The type parameter is identified in each package by its object
+	// path, so that its constraint belongs to the same realm of types
+	// as the candidate types.
+	declPkgPath := PackagePath(tname.Pkg().Path())
+	path, err := objectpath.For(tname)
+	if err != nil {
+		return nil // e.g. type parameter of a local type
+	}
+	declURI := protocol.URIFromPath(pkg.FileSet().File(tname.Pos()).Name())
+	pkgs, err := typeCheckReverseDependencies(ctx, snapshot, declURI, true)
+	if err != nil {
+		return err
+	}
+	reported := make(map[string]bool) // package path and name of each reported type
+	for _, pkg := range pkgs {
+		if !snapshot.IsWorkspacePackage(pkg.Metadata().ID) {
+			continue
+		}
+		declPkg := pkg.Types()
+		if pkg.Metadata().PkgPath != declPkgPath {
+			declPkg = pkg.DependencyTypes(declPkgPath)
+			if declPkg == nil {
+				continue
+			}
+		}
+		obj, err := objectpath.Object(declPkg, path)
+		if err != nil {
+			continue
+		}
+		tparam, ok := obj.Type().(*types.TypeParam)
+		if !ok {
+			continue
+		}
+		iface, ok := tparam.Constraint().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		candidates := []*types.Package{pkg.Types()}
+		for _, imp := range pkg.Types().Imports() {
+			if id, ok := pkg.Metadata().DepsByPkgPath[PackagePath(imp.Path())]; ok && snapshot.IsWorkspacePackage(id) {
+				candidates = append(candidates, imp)
+			}
+		}
+		for _, cand := range candidates {
+			if err := typesSatisfying(ctx, snapshot, pkg, cand, iface, reported, yield); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// typesSatisfying reports the package-level types of package cand, or
+// pointers to them, that satisfy the constraint iface, both in the
+// realm of the type-checked package pkg. The types of a package other
+// than pkg must be exported. The reported map holds the package path
+// and name of each type already reported, perhaps in another realm.
+func typesSatisfying(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, cand *types.Package, iface *types.Interface, reported map[string]bool, yield implYieldFunc) error {
+	scope := cand.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() || cand != pkg.Types() && !obj.Exported() {
+			continue
+		}
+		if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			continue // generic types must be instantiated
+		}
+		t := obj.Type()
+		if iface, ok := t.Underlying().(*types.Interface); ok && !iface.IsMethodSet() {
+			continue // constraint interfaces are not type arguments
+		}
+		switch {
+		case types.Satisfies(t, iface):
+		case !types.IsInterface(t) && types.Satisfies(types.NewPointer(t), iface):
+			name = "*" + name
+		default:
+			continue
+		}
+		key := cand.Path() + "." + name
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+		loc, err := ObjectLocation(ctx, pkg.FileSet(), snapshot, obj)
+		if err != nil {
+			return err
+		}
+		yield(PackagePath(cand.Path()), name, types.IsInterface(t), loc)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"slices"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/metadata"
@@ -302,6 +303,28 @@ func boolCompare(x, y bool) int {
 	return btoi(x) - btoi(y)
 }
 
+// exportedName returns name with an upper case initial letter, and
+// reports whether its initial letter was lower case. Otherwise it
+// returns name unchanged.
+func exportedName(name string) (string, bool) {
+	r, size := utf8.DecodeRuneInString(name)
+	if !unicode.IsLower(r) {
+		return name, false
+	}
+	return string(unicode.ToUpper(r)) + name[size:], true
+}
+
+// unexportedName returns name with a lower case initial letter, and
+// reports whether its initial letter was upper case. Otherwise it
+// returns name unchanged.
+func unexportedName(name string) (string, bool) {
+	r, size := utf8.DecodeRuneInString(name)
+	if !unicode.IsUpper(r) {
+		return name, false
+	}
+	return string(unicode.ToLower(r)) + name[size:], true
+}
+
 // AbbreviateVarName returns an abbreviated var name based on the given full
 // name (which may be a type name, for example).
 //
@@ -24,53 +24,59 @@ import (
 // These commands may be obtained from a CodeLens or CodeAction request
 // and executed by an ExecuteCommand request.
 const (
-	AddDependency           Command = "gopls.add_dependency"
-	AddImport               Command = "gopls.add_import"
-	AddTelemetryCounters    Command = "gopls.add_telemetry_counters"
-	AddTest                 Command = "gopls.add_test"
-	ApplyFix                Command = "gopls.apply_fix"
-	Assembly                Command = "gopls.assembly"
-	ChangeSignature         Command = "gopls.change_signature"
-	CheckUpgrades           Command = "gopls.check_upgrades"
-	ClientOpenURL           Command = "gopls.client_open_url"
-	DiagnoseFiles           Command = "gopls.diagnose_files"
-	Doc                     Command = "gopls.doc"
-	EditGoDirective         Command = "gopls.edit_go_directive"
-	ExtractToNewFile        Command = "gopls.extract_to_new_file"
-	FetchVulncheckResult    Command = "gopls.fetch_vulncheck_result"
-	FreeSymbols             Command = "gopls.free_symbols"
-	GCDetails               Command = "gopls.gc_details"
-	Generate                Command = "gopls.generate"
-	GoGetPackage            Command = "gopls.go_get_package"
-	ImplementInterface      Command = "gopls.implement_interface"
-	ListImports             Command = "gopls.list_imports"
-	ListKnownPackages       Command = "gopls.list_known_packages"
-	LSP                     Command = "gopls.lsp"
-	MaybePromptForTelemetry Command = "gopls.maybe_prompt_for_telemetry"
-	MemStats                Command = "gopls.mem_stats"
-	ModifyTags              Command = "gopls.modify_tags"
-	Modules                 Command = "gopls.modules"
-	MoveType                Command = "gopls.move_type"
-	PackageSymbols          Command = "gopls.package_symbols"
-	Packages                Command = "gopls.packages"
-	RegenerateCgo           Command = "gopls.regenerate_cgo"
-	RemoveDependency        Command = "gopls.remove_dependency"
-	ResetGoModDiagnostics   Command = "gopls.reset_go_mod_diagnostics"
-	RunGoWorkCommand        Command = "gopls.run_go_work_command"
-	RunGovulncheck          Command = "gopls.run_govulncheck"
-	RunTests                Command = "gopls.run_tests"
-	ScanImports             Command = "gopls.scan_imports"
-	SplitPackage            Command = "gopls.split_package"
-	StartDebugging          Command = "gopls.start_debugging"
-	StartProfile            Command = "gopls.start_profile"
-	StopProfile             Command = "gopls.stop_profile"
-	Tidy                    Command = "gopls.tidy"
-	UpdateGoSum             Command = "gopls.update_go_sum"
-	UpgradeDependency       Command = "gopls.upgrade_dependency"
-	Vendor                  Command = "gopls.vendor"
-	Views                   Command = "gopls.views"
-	Vulncheck               Command = "gopls.vulncheck"
-	WorkspaceStats          Command = "gopls.workspace_stats"
+	AddDependency                Command = "gopls.add_dependency"
+	AddImport                    Command = "gopls.add_import"
+	AddTelemetryCounters         Command = "gopls.add_telemetry_counters"
+	AddTest                      Command = "gopls.add_test"
+	ApplyFix                     Command = "gopls.apply_fix"
+	Assembly                     Command = "gopls.assembly"
+	ChangeSignature              Command = "gopls.change_signature"
+	CheckUpgrades                Command = "gopls.check_upgrades"
+	ClientOpenURL                Command = "gopls.client_open_url"
+	ConvertFromFunctionalOptions Command = "gopls.convert_from_functional_options"
+	ConvertToFunctionalOptions   Command = "gopls.convert_to_functional_options"
+	Coverage                     Command = "gopls.coverage"
+	DiagnoseFiles                Command = "gopls.diagnose_files"
+	Doc                          Command = "gopls.doc"
+	EditGoDirective              Command = "gopls.edit_go_directive"
+	ExtractToNewFile             Command = "gopls.extract_to_new_file"
+	FetchVulncheckResult         Command = "gopls.fetch_vulncheck_result"
+	FreeSymbols                  Command = "gopls.free_symbols"
+	GCDetails                    Command = "gopls.gc_details"
+	Generate                     Command = "gopls.generate"
+	GoGetPackage                 Command = "gopls.go_get_package"
+	ImplementInterface           Command = "gopls.implement_interface"
+	InlineAllCalls               Command = "gopls.inline_all_calls"
+	IntroduceParamObject         Command = "gopls.introduce_param_object"
+	ListImports                  Command = "gopls.list_imports"
+	ListKnownPackages            Command = "gopls.list_known_packages"
+	LSP                          Command = "gopls.lsp"
+	MaybePromptForTelemetry      Command = "gopls.maybe_prompt_for_telemetry"
+	MemStats                     Command = "gopls.mem_stats"
+	ModifyTags                   Command = "gopls.modify_tags"
+	Modules                      Command = "gopls.modules"
+	MoveDecl                     Command = "gopls.move_decl"
+	MoveType                     Command = "gopls.move_type"
+	PackageSymbols               Command = "gopls.package_symbols"
+	Packages                     Command = "gopls.packages"
+	RegenerateCgo                Command = "gopls.regenerate_cgo"
+	RemoveDependency             Command = "gopls.remove_dependency"
+	ResetGoModDiagnostics        Command = "gopls.reset_go_mod_diagnostics"
+	RunGoWorkCommand             Command = "gopls.run_go_work_command"
+	RunGovulncheck               Command = "gopls.run_govulncheck"
+	RunTests                     Command = "gopls.run_tests"
+	ScanImports                  Command = "gopls.scan_imports"
+	SplitPackage                 Command = "gopls.split_package"
+	StartDebugging               Command = "gopls.start_debugging"
+	StartProfile                 Command = "gopls.start_profile"
+	StopProfile                  Command = "gopls.stop_profile"
+	Tidy                         Command = "gopls.tidy"
+	UpdateGoSum                  Command = "gopls.update_go_sum"
+	UpgradeDependency            Command = "gopls.upgrade_dependency"
+	Vendor                       Command = "gopls.vendor"
+	Views                        Command = "gopls.views"
+	Vulncheck                    Command = "gopls.vulncheck"
+	WorkspaceStats               Command = "gopls.workspace_stats"
 )
 
 var Commands = []Command{
@@ -83,6 +89,9 @@ var Commands = []Command{
 	ChangeSignature,
 	CheckUpgrades,
 	ClientOpenURL,
+	ConvertFromFunctionalOptions,
+	ConvertToFunctionalOptions,
+	Coverage,
 	DiagnoseFiles,
 	Doc,
 	EditGoDirective,
@@ -93,6 +102,8 @@ var Commands = []Command{
 	Generate,
 	GoGetPackage,
 	ImplementInterface,
+	InlineAllCalls,
+	IntroduceParamObject,
 	ListImports,
 	ListKnownPackages,
 	LSP,
@@ -100,6 +111,7 @@ var Commands = []Command{
 	MemStats,
 	ModifyTags,
 	Modules,
+	MoveDecl,
 	MoveType,
 	PackageSymbols,
 	Packages,
@@ -181,6 +193,24 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.ClientOpenURL(ctx, a0)
+	case ConvertFromFunctionalOptions:
+		var a0 ConvertFromFunctionalOptionsArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.ConvertFromFunctionalOptions(ctx, a0)
+	case ConvertToFunctionalOptions:
+		var a0 ConvertToFunctionalOptionsArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.ConvertToFunctionalOptions(ctx, a0)
+	case Coverage:
+		var a0 CoverageArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.Coverage(ctx, a0)
 	case DiagnoseFiles:
 		var a0 DiagnoseFilesArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -242,6 +272,18 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.ImplementInterface(ctx, a0, &params.InteractiveParams)
+	case InlineAllCalls:
+		var a0 InlineAllCallsArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.InlineAllCalls(ctx, a0)
+	case IntroduceParamObject:
+		var a0 IntroduceParamObjectArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.IntroduceParamObject(ctx, a0)
 	case ListImports:
 		var a0 URIArg
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -276,6 +318,12 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return s.Modules(ctx, a0)
+	case MoveDecl:
+		var a0 MoveDeclArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.MoveDecl(ctx, a0, &params.InteractiveParams)
 	case MoveType:
 		var a0 MoveTypeArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -467,6 +515,30 @@ func NewClientOpenURLCommand(title string, a0 string) *protocol.Command {
 	}
 }
 
+func NewConvertFromFunctionalOptionsCommand(title string, a0 ConvertFromFunctionalOptionsArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   ConvertFromFunctionalOptions.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewConvertToFunctionalOptionsCommand(title string, a0 ConvertToFunctionalOptionsArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   ConvertToFunctionalOptions.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewCoverageCommand(title string, a0 CoverageArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   Coverage.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewDiagnoseFilesCommand(title string, a0 DiagnoseFilesArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -547,6 +619,22 @@ func NewImplementInterfaceCommand(title string, a0 ImplementInterfaceArgs) *prot
 	}
 }
 
+func NewInlineAllCallsCommand(title string, a0 InlineAllCallsArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   InlineAllCalls.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewIntroduceParamObjectCommand(title string, a0 IntroduceParamObjectArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   IntroduceParamObject.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewListImportsCommand(title string, a0 URIArg) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -603,6 +691,14 @@ func NewModulesCommand(title string, a0 ModulesArgs) *protocol.Command {
 	}
 }
 
+func NewMoveDeclCommand(title string, a0 MoveDeclArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   MoveDecl.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewMoveTypeCommand(title string, a0 MoveTypeArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
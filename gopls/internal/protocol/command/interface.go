@@ -143,6 +143,20 @@ type Interface interface {
 	// client-side logic in VS Code.)
 	GCDetails(context.Context, protocol.DocumentURI) error
 
+	// Coverage: Show test coverage
+	//
+	// Reports the statements of a package that are not covered by
+	// its tests as diagnostics of information severity, and the
+	// percentage of the statements of each function that are
+	// covered in hover. The coverage information is read from a
+	// profile written by `go test -coverprofile`, or, if none is
+	// specified, from one produced by running the tests of the
+	// package; it is updated whenever the profile changes.
+	//
+	// When it runs the tests, this command is asynchronous; clients
+	// must wait for the 'end' progress notification.
+	Coverage(context.Context, CoverageArgs) error
+
 	// LSP is a command that functions as a generic dispatcher, allowing clients
 	// to execute any LSP RPC through the "workspace/executeCommand" request.
 	//
@@ -272,6 +286,37 @@ type Interface interface {
 	// Its signature will certainly change in the future (pun intended).
 	ChangeSignature(context.Context, ChangeSignatureArgs) (*protocol.WorkspaceEdit, error)
 
+	// InlineAllCalls: Inline all calls to a function
+	//
+	// Inlines every call, throughout the workspace, to the function
+	// called at the specified location, which may be declared in a
+	// dependency such as a module in the module cache. In preview mode,
+	// the changes are not applied. In either case, the result is a
+	// unified diff of the affected files, and the calls outside the
+	// workspace, which are not inlined.
+	InlineAllCalls(context.Context, InlineAllCallsArgs) (InlineAllCallsResult, error)
+
+	// IntroduceParamObject: Replace parameters by a struct
+	//
+	// Replaces the selected consecutive parameters of a function by a
+	// single parameter of a new struct type, and updates all calls.
+	IntroduceParamObject(context.Context, IntroduceParamObjectArgs) (*protocol.WorkspaceEdit, error)
+
+	// ConvertToFunctionalOptions: Replace parameters by functional options
+	//
+	// Replaces the selected consecutive parameters of a function by a
+	// final variadic parameter of a new option type, declares a
+	// constructor of an option for each parameter, and updates all
+	// calls.
+	ConvertToFunctionalOptions(context.Context, ConvertToFunctionalOptionsArgs) (*protocol.WorkspaceEdit, error)
+
+	// ConvertFromFunctionalOptions: Replace functional options by parameters
+	//
+	// Replaces the final variadic parameter of functional options of a
+	// function by a parameter for each option, and updates all calls.
+	// It is the inverse of ConvertToFunctionalOptions.
+	ConvertFromFunctionalOptions(context.Context, ConvertFromFunctionalOptionsArgs) (*protocol.WorkspaceEdit, error)
+
 	// DiagnoseFiles: Cause server to publish diagnostics for the specified files.
 	//
 	// This command is needed by the 'gopls {check,fix}' CLI subcommands.
@@ -334,6 +379,12 @@ type Interface interface {
 	// MoveType: Move a type declaration to a different package.
 	MoveType(context.Context, MoveTypeArgs) error
 
+	// MoveDecl: Move a declaration to a different package.
+	//
+	// Moves a package-level declaration, with the methods of a declared
+	// type, to another package of the workspace, updating references.
+	MoveDecl(context.Context, MoveDeclArgs, *protocol.InteractiveParams) error
+
 	// ImplementInterface: Add methods to a type to implement an interface.
 	ImplementInterface(context.Context, ImplementInterfaceArgs, *protocol.InteractiveParams) error
 }
@@ -349,6 +400,18 @@ type RunTestsArgs struct {
 	Benchmarks []string
 }
 
+type CoverageArgs struct {
+	// A file of the package whose coverage is shown.
+	URI protocol.DocumentURI
+
+	// The coverage profile to read. If empty, the tests of the
+	// package are run to produce one.
+	Profile protocol.DocumentURI
+
+	// Hide the coverage information of the package.
+	Hide bool
+}
+
 type GenerateArgs struct {
 	// URI for the directory to generate.
 	Dir protocol.DocumentURI
@@ -677,6 +740,61 @@ func (a ChangeSignatureParam) MarshalJSON() ([]byte, error) {
 	return json.Marshal(a.OldIndex)
 }
 
+// InlineAllCallsArgs specifies an "inline all calls" refactoring to
+// perform.
+type InlineAllCallsArgs struct {
+	// Location is a range within a call to the function.
+	Location protocol.Location
+
+	// Whether to return the changes without applying them.
+	Preview bool
+}
+
+// InlineAllCallsResult is the result of an "inline all calls"
+// refactoring.
+type InlineAllCallsResult struct {
+	// Diff is a unified diff of the changes to the affected files.
+	Diff string
+
+	// Skipped holds the calls outside the workspace, such as those
+	// within the dependency that declares the function, which are
+	// read-only and so are not inlined.
+	Skipped []protocol.Location
+}
+
+// IntroduceParamObjectArgs specifies an "introduce parameter object"
+// refactoring to perform.
+type IntroduceParamObjectArgs struct {
+	// Location is the selection of the parameters to replace, within the
+	// function signature.
+	Location protocol.Location
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
+// ConvertToFunctionalOptionsArgs specifies a "convert to functional
+// options" refactoring to perform.
+type ConvertToFunctionalOptionsArgs struct {
+	// Location is the selection of the parameters to replace, within the
+	// function signature.
+	Location protocol.Location
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
+// ConvertFromFunctionalOptionsArgs specifies a "convert from
+// functional options" refactoring to perform.
+type ConvertFromFunctionalOptionsArgs struct {
+	// Location is the selection of the options parameter, within the
+	// function signature.
+	Location protocol.Location
+
+	// Whether to resolve and return the edits.
+	ResolveEdits bool
+}
+
 // DiagnoseFilesArgs specifies a set of files for which diagnostics are wanted.
 type DiagnoseFilesArgs struct {
 	Files []protocol.DocumentURI
@@ -907,6 +1025,18 @@ type LSPArgs struct {
 	Param  json.RawMessage `json:"param"`
 }
 
+// MoveDeclArgs specifies a "move declaration" refactoring to perform.
+type MoveDeclArgs struct {
+	// The location of the name of the declaration to move, or of the
+	// keyword of a var, const, or type declaration.
+	Location protocol.Location
+	// The file or directory to move the declaration to: a URI, or a
+	// file path relative to the directory of Location. A file is
+	// created if it does not exist. If Dest is empty, it is provided
+	// by the user through a dialog.
+	Dest string
+}
+
 // MoveTypeArgs specifies a "move type" refactoring to perform.
 type MoveTypeArgs struct {
 	// The location of the type to move.
@@ -200,3 +200,25 @@ func Encode(
 	}
 	return code
 }
+
+// Diff returns a single edit that transforms the encoded tokens old
+// into new: it replaces the deleteCount elements of old starting at
+// start by the elements of insert. The edit excludes the longest
+// common prefix and suffix of the two sequences, so it is empty
+// (deleteCount == 0 && len(insert) == 0) if they are equal.
+//
+// Because tokens are delta-encoded relative to their predecessor, an
+// edit in one part of a file typically changes only the few tokens
+// nearest to it.
+func Diff(old, new []uint32) (start, deleteCount int, insert []uint32) {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix &&
+		old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+	return prefix, len(old) - prefix - suffix, new[prefix : len(new)-suffix]
+}
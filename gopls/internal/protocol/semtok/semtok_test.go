@@ -0,0 +1,44 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package semtok_test
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol/semtok"
+)
+
+func TestDiff(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		old, new []uint32
+		start    int
+		delete   int
+		insert   []uint32
+	}{
+		{"equal", []uint32{1, 2, 3}, []uint32{1, 2, 3}, 3, 0, nil},
+		{"empty", nil, nil, 0, 0, nil},
+		{"from empty", nil, []uint32{1, 2}, 0, 0, []uint32{1, 2}},
+		{"to empty", []uint32{1, 2}, nil, 0, 2, nil},
+		{"insert middle", []uint32{1, 2, 5, 6}, []uint32{1, 2, 3, 4, 5, 6}, 2, 0, []uint32{3, 4}},
+		{"delete middle", []uint32{1, 2, 3, 4, 5, 6}, []uint32{1, 2, 5, 6}, 2, 2, nil},
+		{"replace", []uint32{1, 2, 3, 4, 5}, []uint32{1, 9, 9, 5}, 1, 3, []uint32{9, 9}},
+		{"repeated", []uint32{0, 0, 0}, []uint32{0, 0, 0, 0}, 3, 0, []uint32{0}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			start, deleteCount, insert := semtok.Diff(test.old, test.new)
+			if start != test.start || deleteCount != test.delete || !slices.Equal(insert, test.insert) {
+				t.Errorf("Diff(%v, %v) = (%d, %d, %v), want (%d, %d, %v)",
+					test.old, test.new, start, deleteCount, insert, test.start, test.delete, test.insert)
+			}
+			// Applying the edit to old must yield new.
+			got := slices.Concat(test.old[:start], insert, test.old[start+deleteCount:])
+			if !slices.Equal(got, test.new) && len(got)+len(test.new) > 0 {
+				t.Errorf("applying edit to %v = %v, want %v", test.old, got, test.new)
+			}
+		})
+	}
+}
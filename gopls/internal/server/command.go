@@ -1069,6 +1069,78 @@ func (c *commandHandler) GCDetails(ctx context.Context, uri protocol.DocumentURI
 	})
 }
 
+func (c *commandHandler) Coverage(ctx context.Context, args command.CoverageArgs) error {
+	if args.Hide || args.Profile != "" {
+		return c.run(ctx, commandConfig{
+			forURI: args.URI,
+		}, func(ctx context.Context, deps commandDeps) error {
+			return c.showCoverage(ctx, deps, args.Profile)
+		})
+	}
+	return c.run(ctx, commandConfig{
+		progress:    "Running go test with coverage", // (asynchronous)
+		requireSave: true,                            // go test honors overlays, but tests themselves cannot
+		forURI:      args.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		jsonrpc2.Async(ctx) // don't block RPCs behind this command, since it can take a while
+
+		dir := args.URI.Dir()
+		profile, err := golang.DefaultCoverageProfile(c.s.session, dir)
+		if err != nil {
+			return err
+		}
+		buf := &bytes.Buffer{}
+		ew := progress.NewEventWriter(ctx, "test")
+		out := io.MultiWriter(ew, progress.NewWorkDoneWriter(ctx, deps.work), buf)
+		testErr := golang.RunCoverage(ctx, deps.snapshot, dir, profile, out)
+		if errors.Is(testErr, context.Canceled) {
+			return testErr
+		}
+		if _, err := os.Stat(profile.Path()); err != nil {
+			// The tests did not run (e.g. a build error).
+			return fmt.Errorf("go test produced no coverage profile: %v\n%s", testErr, buf)
+		}
+		if testErr != nil {
+			showMessage(ctx, c.s.client, protocol.Warning, "tests failed; showing coverage of the tests that ran\n"+buf.String())
+		}
+		return c.showCoverage(ctx, deps, profile)
+	})
+}
+
+// showCoverage shows the coverage information in the specified profile
+// for the package of deps.fh, or hides it if profile is empty.
+func (c *commandHandler) showCoverage(ctx context.Context, deps commandDeps, profile protocol.DocumentURI) error {
+	err := c.modifyState(ctx, FromCoverage, func() (*cache.Snapshot, func(), error) {
+		// Don't blindly use "dir := deps.fh.URI().Dir()"; validate.
+		meta, err := deps.snapshot.NarrowestMetadataForFile(ctx, deps.fh.URI())
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(meta.CompiledGoFiles) == 0 {
+			return nil, nil, fmt.Errorf("package %q does not compile file %q", meta.ID, deps.fh.URI())
+		}
+		dir := meta.CompiledGoFiles[0].Dir()
+
+		changed := cache.StateChange{
+			Coverage: map[protocol.DocumentURI]protocol.DocumentURI{dir: profile},
+		}
+		if profile != "" {
+			// Discard any stale contents of the profile.
+			fh, err := c.s.session.ReadFile(ctx, profile)
+			if err != nil {
+				return nil, nil, err
+			}
+			changed.Files = map[protocol.DocumentURI]file.Handle{profile: fh}
+		}
+		return c.s.session.InvalidateView(ctx, deps.snapshot.View(), changed)
+	})
+	if err != nil {
+		return err
+	}
+	// Watch the profile for changes.
+	return c.s.updateWatchedDirectories(ctx)
+}
+
 func (c *commandHandler) ListKnownPackages(ctx context.Context, args command.URIArg) (command.ListKnownPackagesResult, error) {
 	var result command.ListKnownPackagesResult
 	err := c.run(ctx, commandConfig{
@@ -1671,6 +1743,96 @@ func (c *commandHandler) ChangeSignature(ctx context.Context, args command.Chang
 	return result, err
 }
 
+func (c *commandHandler) InlineAllCalls(ctx context.Context, args command.InlineAllCallsArgs) (command.InlineAllCallsResult, error) {
+	var result command.InlineAllCallsResult
+	err := c.run(ctx, commandConfig{
+		progress: "Inlining calls",
+		forURI:   args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		pkg, pgf, err := golang.NarrowestPackageForFile(ctx, deps.snapshot, args.Location.URI)
+		if err != nil {
+			return err
+		}
+		changes, diff, skipped, err := golang.InlineAllCalls(ctx, deps.snapshot, pkg, pgf, args.Location.Range)
+		if err != nil {
+			return err
+		}
+		result.Diff = diff
+		result.Skipped = skipped
+		if args.Preview {
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return result, err
+}
+
+func (c *commandHandler) IntroduceParamObject(ctx context.Context, args command.IntroduceParamObjectArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		pkg, pgf, err := golang.NarrowestPackageForFile(ctx, deps.snapshot, args.Location.URI)
+		if err != nil {
+			return err
+		}
+		docedits, err := golang.IntroduceParamObject(ctx, deps.snapshot, pkg, pgf, args.Location.Range)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(docedits...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, docedits)
+	})
+	return result, err
+}
+
+func (c *commandHandler) ConvertToFunctionalOptions(ctx context.Context, args command.ConvertToFunctionalOptionsArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		pkg, pgf, err := golang.NarrowestPackageForFile(ctx, deps.snapshot, args.Location.URI)
+		if err != nil {
+			return err
+		}
+		docedits, err := golang.ConvertToFunctionalOptions(ctx, deps.snapshot, pkg, pgf, args.Location.Range)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(docedits...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, docedits)
+	})
+	return result, err
+}
+
+func (c *commandHandler) ConvertFromFunctionalOptions(ctx context.Context, args command.ConvertFromFunctionalOptionsArgs) (*protocol.WorkspaceEdit, error) {
+	var result *protocol.WorkspaceEdit
+	err := c.run(ctx, commandConfig{
+		forURI: args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		pkg, pgf, err := golang.NarrowestPackageForFile(ctx, deps.snapshot, args.Location.URI)
+		if err != nil {
+			return err
+		}
+		docedits, err := golang.ConvertFromFunctionalOptions(ctx, deps.snapshot, pkg, pgf, args.Location.Range)
+		if err != nil {
+			return err
+		}
+		if args.ResolveEdits {
+			result = protocol.NewWorkspaceEdit(docedits...)
+			return nil
+		}
+		return applyChanges(ctx, c.s.client, docedits)
+	})
+	return result, err
+}
+
 func (c *commandHandler) DiagnoseFiles(ctx context.Context, args command.DiagnoseFilesArgs) error {
 	return c.run(ctx, commandConfig{
 		progress: "Diagnose files",
@@ -1937,6 +2099,27 @@ func parseTransform(input string) (modifytags.Transform, error) {
 	}
 }
 
+func (c *commandHandler) MoveDecl(ctx context.Context, args command.MoveDeclArgs, params *protocol.InteractiveParams) error {
+	return c.run(ctx, commandConfig{
+		progress: "Moving declaration",
+		forURI:   args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		dest := args.Dest
+		if dest == "" {
+			var err error
+			dest, err = golang.FormAnswer[string](params, "destination")
+			if err != nil {
+				return err
+			}
+		}
+		changes, err := golang.MoveDecl(ctx, deps.snapshot, args.Location, dest)
+		if err != nil {
+			return err
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+}
+
 func (c *commandHandler) MoveType(ctx context.Context, args command.MoveTypeArgs) error {
 	err := c.run(ctx, commandConfig{
 		forURI: args.Location.URI,
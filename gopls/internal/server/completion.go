@@ -6,6 +6,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -147,19 +148,6 @@ func toProtocolCompletionItems(candidates []completion.CompletionItem, surroundi
 			continue
 		}
 
-		var doc *protocol.Or_CompletionItem_documentation
-		if candidate.Documentation != "" {
-			var value any
-			if options.PreferredContentFormat == protocol.Markdown {
-				value = protocol.MarkupContent{
-					Kind:  protocol.Markdown,
-					Value: golang.DocCommentToMarkdown(candidate.Documentation, options),
-				}
-			} else {
-				value = candidate.Documentation
-			}
-			doc = &protocol.Or_CompletionItem_documentation{Value: value}
-		}
 		var edits *protocol.Or_CompletionItem_textEdit
 		if options.InsertReplaceSupported {
 			insertRng := insertRng0
@@ -202,11 +190,79 @@ func toProtocolCompletionItems(candidates []completion.CompletionItem, surroundi
 			FilterText: strings.TrimLeft(candidate.InsertText, "&*"),
 
 			Preselect:     i == 0,
-			Documentation: doc,
+			Documentation: completionDocumentation(candidate.Documentation, options),
 			Tags:          protocol.NonNilSlice(candidate.Tags),
 			Deprecated:    candidate.Deprecated,
 		}
+		if candidate.Resolve != nil {
+			item.Data = candidate.Resolve
+		}
 		items = append(items, item)
 	}
 	return items, nil
 }
+
+// completionDocumentation returns the documentation of a completion
+// item in the client's preferred format, or nil if doc is empty.
+func completionDocumentation(doc string, options *settings.Options) *protocol.Or_CompletionItem_documentation {
+	if doc == "" {
+		return nil
+	}
+	var value any
+	if options.PreferredContentFormat == protocol.Markdown {
+		value = protocol.MarkupContent{
+			Kind:  protocol.Markdown,
+			Value: golang.DocCommentToMarkdown(doc, options),
+		}
+	} else {
+		value = doc
+	}
+	return &protocol.Or_CompletionItem_documentation{Value: value}
+}
+
+// ResolveCompletionItem computes the properties of a completion item
+// that were deferred by Completion for a client that can resolve them
+// lazily: its documentation and deprecation tags, and the additional
+// edits that import the package of an unimported candidate. The
+// information required to compute them is held in the Data field of
+// the item.
+func (s *server) ResolveCompletionItem(ctx context.Context, item *protocol.CompletionItem) (*protocol.CompletionItem, error) {
+	ctx, done := event.Start(ctx, "server.ResolveCompletionItem")
+	defer done()
+
+	if item.Data == nil {
+		return item, nil // nothing to resolve
+	}
+	var data completion.ResolveData
+	raw, err := json.Marshal(item.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid completion item data: %v", err)
+	}
+
+	fh, snapshot, release, err := s.session.FileOf(ctx, data.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if snapshot.FileKind(fh) != file.Go {
+		return item, nil
+	}
+	res, err := completion.Resolve(ctx, snapshot, &data)
+	if err != nil {
+		return nil, err
+	}
+	options := snapshot.Options()
+	if doc := completionDocumentation(res.Documentation, options); doc != nil {
+		item.Documentation = doc
+	}
+	if res.Tags != nil {
+		item.Tags = res.Tags
+	}
+	item.Deprecated = item.Deprecated || res.Deprecated
+	item.AdditionalTextEdits = append(item.AdditionalTextEdits, res.AdditionalTextEdits...)
+	item.Data = nil // resolved
+	return item, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/label"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// Declaration defines the textDocument/declaration feature, which
+// reports the abstract declarations of a symbol: the interface methods
+// satisfied by a concrete method, or the embedded field through which a
+// field is promoted.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_declaration.
+func (s *server) Declaration(ctx context.Context, params *protocol.DeclarationParams) (*protocol.Or_textDocument_declaration, error) {
+	ctx, done := event.Start(ctx, "server.Declaration", label.URI.Of(params.TextDocument.URI))
+	defer done()
+
+	fh, snapshot, release, err := s.session.FileOf(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	switch kind := snapshot.FileKind(fh); kind {
+	case file.Go:
+		locs, err := golang.Declaration(ctx, snapshot, fh, params.Range)
+		if err != nil {
+			return nil, err
+		}
+		return &protocol.Or_textDocument_declaration{Value: protocol.Declaration(locs)}, nil
+	default:
+		return nil, fmt.Errorf("can't find declarations for file type %s", kind)
+	}
+}
@@ -76,10 +76,207 @@ func (s *server) Diagnostic(ctx context.Context, params *protocol.DocumentDiagno
 	}, nil
 }
 
+// DiagnosticWorkspace implements the workspace/diagnostic LSP request,
+// reporting the diagnostics of each file, whether or not the file is open.
+//
+// The report for each file consists of the diagnostics most recently
+// published for it, computed from s.diagnostics without further diagnosis,
+// and the analysis diagnostics of the workspace packages that have no open
+// files, which are not published (see [server.analyzeClosedPackages]).
+//
+// Each document report carries a result ID derived from the hash of its
+// diagnostics. If the client supplies a matching previous result ID for a
+// document, an "unchanged" report is returned in place of the full set.
+func (s *server) DiagnosticWorkspace(ctx context.Context, params *protocol.WorkspaceDiagnosticParams) (*protocol.WorkspaceDiagnosticReport, error) {
+	ctx, done := event.Start(ctx, "server.DiagnosticWorkspace")
+	defer done()
+
+	jsonrpc2.Async(ctx) // allow asynchronous analysis
+
+	previous := make(map[protocol.DocumentURI]string)
+	for _, id := range params.PreviousResultIds {
+		previous[id.URI] = id.Value
+	}
+
+	analysisDiags, err := s.analyzeClosedPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.diagnosticsMu.Lock()
+	defer s.diagnosticsMu.Unlock()
+
+	uris := make(map[protocol.DocumentURI]unit)
+	for uri := range s.diagnostics {
+		uris[uri] = unit{}
+	}
+	for uri := range analysisDiags {
+		uris[uri] = unit{}
+	}
+
+	report := &protocol.WorkspaceDiagnosticReport{
+		Items: []protocol.WorkspaceDocumentDiagnosticReport{}, // non-nil
+	}
+	for uri := range moremaps.Sorted(uris) {
+		var (
+			diags   []*cache.Diagnostic
+			hash    file.Hash
+			version int32
+		)
+		if f, ok := s.diagnostics[uri]; ok {
+			diags, hash, version = f.published, f.publishedHash, f.publishedVersion
+		}
+		if adiags := analysisDiags[uri]; len(adiags) > 0 {
+			diags = golang.CombineDiagnostics(diags, adiags)
+			for _, diag := range adiags {
+				hash.XORWith(diag.Hash())
+			}
+			sortDiagnostics(diags)
+		}
+		resultID := hash.String()
+		var item any
+		if previous[uri] == resultID {
+			item = protocol.WorkspaceUnchangedDocumentDiagnosticReport{
+				URI:     uri,
+				Version: version,
+				UnchangedDocumentDiagnosticReport: protocol.UnchangedDocumentDiagnosticReport{
+					Kind:     string(protocol.DiagnosticUnchanged),
+					ResultID: resultID,
+				},
+			}
+		} else {
+			item = protocol.WorkspaceFullDocumentDiagnosticReport{
+				URI:     uri,
+				Version: version,
+				FullDocumentDiagnosticReport: protocol.FullDocumentDiagnosticReport{
+					Kind:     string(protocol.DiagnosticFull),
+					ResultID: resultID,
+					Items:    cache.ToProtocolDiagnostics(diags...),
+				},
+			}
+		}
+		report.Items = append(report.Items, protocol.WorkspaceDocumentDiagnosticReport{Value: item})
+	}
+	return report, nil
+}
+
+// workspaceAnalysis holds the analysis diagnostics of the workspace
+// packages of a view that have no open files.
+type workspaceAnalysis struct {
+	snapshot    uint64 // snapshot sequence ID
+	diagnostics diagMap
+}
+
+// analyzeClosedPackages returns the analysis diagnostics of the workspace
+// packages of each view that have no open files, which [server.diagnose]
+// does not analyze. The result for each view is memoized until the view's
+// snapshot changes.
+func (s *server) analyzeClosedPackages(ctx context.Context) (diagMap, error) {
+	views := s.session.Views()
+
+	s.workspaceAnalysisMu.Lock()
+	defer s.workspaceAnalysisMu.Unlock()
+
+	for v := range s.workspaceAnalysis {
+		if !slices.Contains(views, v) {
+			delete(s.workspaceAnalysis, v)
+		}
+	}
+
+	diagnostics := make(diagMap)
+	seen := make(map[protocol.DocumentURI]map[file.Hash]bool) // to de-dup diagnostics across views
+	for _, v := range views {
+		snapshot, release, err := v.Snapshot()
+		if err != nil {
+			continue // view is shut down
+		}
+		wa, ok := s.workspaceAnalysis[v]
+		if !ok || wa.snapshot != snapshot.SequenceID() {
+			diags, err := s.analyzeClosedSnapshotPackages(ctx, snapshot)
+			if err != nil {
+				release()
+				return nil, err
+			}
+			wa = workspaceAnalysis{snapshot: snapshot.SequenceID(), diagnostics: diags}
+			s.workspaceAnalysis[v] = wa
+		}
+		release()
+
+		for uri, diags := range wa.diagnostics {
+			if seen[uri] == nil {
+				seen[uri] = make(map[file.Hash]bool)
+			}
+			for _, diag := range diags {
+				if h := diag.Hash(); !seen[uri][h] {
+					seen[uri][h] = true
+					diagnostics[uri] = append(diagnostics[uri], diag)
+				}
+			}
+		}
+	}
+	return diagnostics, nil
+}
+
+// analyzeClosedSnapshotPackages returns the analysis diagnostics of the
+// workspace packages of the snapshot for which no variant has an open
+// file, using the widest variant of each package, as in [server.diagnose].
+func (s *server) analyzeClosedSnapshotPackages(ctx context.Context, snapshot *cache.Snapshot) (diagMap, error) {
+	workspacePkgs, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Packages with an open file are analyzed by diagnose.
+	open := make(map[golang.PackagePath]bool)
+	for _, mp := range workspacePkgs {
+		if slices.ContainsFunc(mp.CompiledGoFiles, snapshot.IsOpen) {
+			open[mp.PkgPath] = true
+		}
+	}
+	var (
+		toAnalyze       = make(map[metadata.PackageID]*metadata.Package)
+		toAnalyzeWidest = make(map[golang.PackagePath]*metadata.Package)
+	)
+	for _, mp := range workspacePkgs {
+		if open[mp.PkgPath] || !slices.ContainsFunc(mp.CompiledGoFiles, func(uri protocol.DocumentURI) bool {
+			return !snapshot.IgnoredFile(uri)
+		}) {
+			continue
+		}
+		if prev, ok := toAnalyzeWidest[mp.PkgPath]; ok {
+			if len(prev.CompiledGoFiles) >= len(mp.CompiledGoFiles) {
+				continue
+			}
+			delete(toAnalyze, prev.ID)
+		}
+		toAnalyze[mp.ID] = mp
+		toAnalyzeWidest[mp.PkgPath] = mp
+	}
+
+	analysisDiags, err := golang.Analyze(ctx, snapshot, toAnalyze, s.progress)
+	if err != nil {
+		return nil, err
+	}
+	// As in diagnose, omit Hint diagnostics, as the files are closed.
+	for uri, diags := range analysisDiags {
+		diags = slices.DeleteFunc(diags, func(diag *cache.Diagnostic) bool {
+			return diag.Severity == protocol.SeverityHint
+		})
+		if len(diags) == 0 {
+			delete(analysisDiags, uri)
+		} else {
+			analysisDiags[uri] = diags
+		}
+	}
+	return analysisDiags, nil
+}
+
 // fileDiagnostics holds the current state of published diagnostics for a file.
 type fileDiagnostics struct {
-	publishedHash file.Hash // hash of the last set of diagnostics published for this URI
-	mustPublish   bool      // if set, publish diagnostics even if they haven't changed
+	publishedHash    file.Hash           // hash of the last set of diagnostics published for this URI
+	published        []*cache.Diagnostic // the last set of diagnostics published for this URI
+	publishedVersion int32               // file version to which the published diagnostics apply
+	mustPublish      bool                // if set, publish diagnostics even if they haven't changed
 
 	// Orphaned file diagnostics are not necessarily associated with any *View
 	// (since they are orphaned). Instead, keep track of the modification ID at
@@ -484,6 +681,11 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 		store("collecting compiler optimization details", compilerOptDetailsDiags, err)
 	})
 
+	wg.Go(func() {
+		coverageDiags, err := s.coverageDiagnostics(ctx, snapshot, toDiagnose)
+		store("collecting coverage", coverageDiags, err)
+	})
+
 	// Package diagnostics and analysis diagnostics must both be computed and
 	// merged before they can be reported.
 	var pkgDiags, analysisDiags diagMap
@@ -577,6 +779,32 @@ func (s *server) compilerOptDetailsDiagnostics(ctx context.Context, snapshot *ca
 	return diagnostics, nil
 }
 
+func (s *server) coverageDiagnostics(ctx context.Context, snapshot *cache.Snapshot, toDiagnose map[metadata.PackageID]*metadata.Package) (diagMap, error) {
+	// Report the statements not covered according to the coverage
+	// profile, if any, of each package directory.
+	//
+	// Each file may belong to several packages (e.g. p and its test
+	// variant), all of which report the same blocks; assigning rather
+	// than appending deduplicates them.
+	diagnostics := make(diagMap)
+	for _, mp := range toDiagnose {
+		if len(mp.CompiledGoFiles) == 0 || mp.IsIntermediateTestVariant() {
+			continue
+		}
+		if snapshot.Coverage(mp.CompiledGoFiles[0].Dir()) == nil {
+			continue
+		}
+		perFileDiags, err := golang.CoverageDiagnostics(ctx, snapshot, mp)
+		if err != nil {
+			return nil, err
+		}
+		for uri, diags := range perFileDiags {
+			diagnostics[uri] = diags
+		}
+	}
+	return diagnostics, nil
+}
+
 // mustPublishDiagnostics marks the uri as needing publication, independent of
 // whether the published contents have changed.
 //
@@ -784,7 +1012,8 @@ func (s *server) updateOrphanedFileDiagnostics(ctx context.Context, modID uint64
 
 // publishFileDiagnosticsLocked publishes a fileDiagnostics value, while holding s.diagnosticsMu.
 //
-// If the publication succeeds, it updates f.publishedHash and f.mustPublish.
+// If the publication succeeds, it updates f.publishedHash, f.published,
+// f.publishedVersion, and f.mustPublish.
 func (s *server) publishFileDiagnosticsLocked(ctx context.Context, views viewSet, uri protocol.DocumentURI, version int32, f *fileDiagnostics) error {
 	// We add a disambiguating suffix (e.g. " [darwin,arm64]") to
 	// each diagnostic that doesn't occur in the default view;
@@ -901,8 +1130,10 @@ func (s *server) publishFileDiagnosticsLocked(ctx context.Context, views viewSet
 			return err
 		}
 		f.publishedHash = hash
+		f.published = unique
 		f.mustPublish = false
 	}
+	f.publishedVersion = version
 	return nil
 }
 
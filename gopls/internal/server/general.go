@@ -114,7 +114,7 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 		diagnosticProvider = &protocol.Or_ServerCapabilities_diagnosticProvider{
 			Value: protocol.DiagnosticOptions{
 				InterFileDependencies: true,
-				WorkspaceDiagnostics:  false, // we don't support workspace/diagnostic
+				WorkspaceDiagnostics:  true,
 			},
 		}
 	}
@@ -176,7 +176,7 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 		// gopls settings at that point allow us to return them.
 		semanticTokenProvider = protocol.SemanticTokensOptions{
 			Range: &protocol.Or_SemanticTokensOptions_range{Value: true},
-			Full:  &protocol.Or_SemanticTokensOptions_full{Value: true},
+			Full:  &protocol.Or_SemanticTokensOptions_full{Value: protocol.SemanticTokensFullDelta{Delta: true}},
 			Legend: protocol.SemanticTokensLegend{
 				TokenTypes:     moreslices.ConvertStrings[string](semtok.Types),
 				TokenModifiers: moreslices.ConvertStrings[string](semtok.Modifiers),
@@ -190,6 +190,20 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 		// is a setting that should ideally live on the front-end.
 	}
 
+	folderKind := protocol.FolderPattern
+	renameFilesOptions := &protocol.FileOperationRegistrationOptions{
+		Filters: []protocol.FileOperationFilter{
+			{
+				Scheme:  "file",
+				Pattern: protocol.FileOperationPattern{Glob: "**/*.go"},
+			},
+			{
+				Scheme:  "file",
+				Pattern: protocol.FileOperationPattern{Glob: "**", Matches: &folderKind},
+			},
+		},
+	}
+
 	versionInfo := debug.VersionInfo()
 
 	goplsVersion, err := json.Marshal(versionInfo)
@@ -204,8 +218,10 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 			CodeLensProvider:      &protocol.CodeLensOptions{}, // must be non-nil to enable the code lens capability
 			CompletionProvider: &protocol.CompletionOptions{
 				TriggerCharacters: []string{"."},
+				ResolveProvider:   true,
 			},
 			DefinitionProvider:         &protocol.Or_ServerCapabilities_definitionProvider{Value: true},
+			DeclarationProvider:        &protocol.Or_ServerCapabilities_declarationProvider{Value: true},
 			TypeDefinitionProvider:     &protocol.Or_ServerCapabilities_typeDefinitionProvider{Value: true},
 			ImplementationProvider:     &protocol.Or_ServerCapabilities_implementationProvider{Value: true},
 			DocumentFormattingProvider: &protocol.Or_ServerCapabilities_documentFormattingProvider{Value: true},
@@ -214,11 +230,19 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
 				Commands: protocol.NonNilSlice(options.SupportedCommands),
 			},
+			DocumentRangeFormattingProvider: &protocol.Or_ServerCapabilities_documentRangeFormattingProvider{
+				Value: protocol.DocumentRangeFormattingOptions{RangesSupport: true},
+			},
+			DocumentOnTypeFormattingProvider: &protocol.DocumentOnTypeFormattingOptions{
+				FirstTriggerCharacter: "\n",
+				MoreTriggerCharacter:  []string{"}"},
+			},
 			FoldingRangeProvider:      &protocol.Or_ServerCapabilities_foldingRangeProvider{Value: true},
 			HoverProvider:             &protocol.Or_ServerCapabilities_hoverProvider{Value: true},
 			DocumentHighlightProvider: &protocol.Or_ServerCapabilities_documentHighlightProvider{Value: true},
 			DocumentLinkProvider:      &protocol.DocumentLinkOptions{},
 			InlayHintProvider:         protocol.InlayHintOptions{},
+			InlineValueProvider:       &protocol.Or_ServerCapabilities_inlineValueProvider{Value: true},
 			DiagnosticProvider:        diagnosticProvider,
 			ReferencesProvider:        &protocol.Or_ServerCapabilities_referencesProvider{Value: true},
 			RenameProvider:            renameOpts,
@@ -237,7 +261,9 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 					IncludeText: false,
 				},
 			},
-			TypeHierarchyProvider: &protocol.Or_ServerCapabilities_typeHierarchyProvider{Value: true},
+			TypeHierarchyProvider:      &protocol.Or_ServerCapabilities_typeHierarchyProvider{Value: true},
+			LinkedEditingRangeProvider: &protocol.Or_ServerCapabilities_linkedEditingRangeProvider{Value: true},
+			MonikerProvider:            &protocol.Or_ServerCapabilities_monikerProvider{Value: true},
 			Workspace: &protocol.WorkspaceOptions{
 				WorkspaceFolders: &protocol.WorkspaceFolders5Gn{
 					Supported:           true,
@@ -251,6 +277,10 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 							Pattern: protocol.FileOperationPattern{Glob: "**/*.go"},
 						}},
 					},
+					// Renaming Go files or directories (which may contain
+					// packages) may require edits to package clauses and imports.
+					WillRename: renameFilesOptions,
+					DidRename:  renameFilesOptions,
 				},
 			},
 			Experimental: map[string]any{
@@ -7,6 +7,9 @@ package server
 import (
 	"context"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/golang"
@@ -70,3 +73,83 @@ func (s *server) PrepareRename(ctx context.Context, params *protocol.PrepareRena
 		Placeholder: item.Text,
 	}, nil
 }
+
+// WillRenameFiles implements the workspace/willRenameFiles request,
+// returning the edits (to package clauses and imports) required by the
+// renaming of Go files and directories that is about to happen.
+func (s *server) WillRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) (*protocol.WorkspaceEdit, error) {
+	ctx, done := event.Start(ctx, "server.WillRenameFiles")
+	defer done()
+
+	if len(params.Files) == 0 {
+		return nil, nil
+	}
+	snapshot, release, err := s.session.SnapshotOf(ctx, params.Files[0].OldURI)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	changes, err := golang.RenameFiles(ctx, snapshot, params.Files)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return protocol.NewWorkspaceEdit(changes...), nil
+}
+
+// DidRenameFiles implements the workspace/didRenameFiles notification.
+// It treats each renaming as the deletion of the old files and the
+// creation of the new ones, without waiting for the corresponding file
+// system events.
+func (s *server) DidRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) error {
+	ctx, done := event.Start(ctx, "server.DidRenameFiles")
+	defer done()
+
+	modifications, err := renameModifications(params.Files)
+	if err != nil {
+		return err
+	}
+	return s.didModifyFiles(ctx, FromDidChangeWatchedFiles, modifications...)
+}
+
+// renameModifications returns the file modifications that result from
+// the specified renamings, which have already happened.
+// The renaming of a directory is expanded into the renaming of
+// each file beneath it, since a modification of a directory does not
+// invalidate the files within it.
+func renameModifications(renames []protocol.FileRename) ([]file.Modification, error) {
+	var modifications []file.Modification
+	rename := func(oldURI, newURI protocol.DocumentURI) {
+		modifications = append(modifications,
+			file.Modification{URI: oldURI, Action: file.Delete, OnDisk: true},
+			file.Modification{URI: newURI, Action: file.Create, OnDisk: true},
+		)
+	}
+	for _, r := range renames {
+		oldDir, newDir := r.OldURI.Path(), r.NewURI.Path()
+		if info, err := os.Stat(newDir); err != nil || !info.IsDir() {
+			rename(r.OldURI, r.NewURI) // a file (or already gone)
+			continue
+		}
+		err := filepath.WalkDir(newDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				rel, err := filepath.Rel(newDir, path)
+				if err != nil {
+					return err
+				}
+				rename(protocol.URIFromPath(filepath.Join(oldDir, rel)), protocol.URIFromPath(path))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing renamed directory: %v", err)
+		}
+	}
+	return modifications, nil
+}
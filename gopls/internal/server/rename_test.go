@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+func TestRenameModifications(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"new/a.go", "new/sub/b.go", "c.go"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	uri := func(name string) protocol.DocumentURI {
+		return protocol.URIFromPath(filepath.Join(dir, name))
+	}
+
+	mods, err := renameModifications([]protocol.FileRename{
+		{OldURI: uri("old"), NewURI: uri("new")},   // directory
+		{OldURI: uri("b.go"), NewURI: uri("c.go")}, // file
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, mod := range mods {
+		rel, err := filepath.Rel(dir, mod.URI.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%v %s", mod.Action, filepath.ToSlash(rel)))
+	}
+	want := []string{
+		"Delete old/a.go",
+		"Create new/a.go",
+		"Delete old/sub/b.go",
+		"Create new/sub/b.go",
+		"Delete b.go",
+		"Create c.go",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("renameModifications = %q, want %q", got, want)
+	}
+}
@@ -6,17 +6,84 @@ package server
 
 import (
 	"context"
+	"strconv"
 
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/golang"
 	"golang.org/x/tools/gopls/internal/label"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/semtok"
 	"golang.org/x/tools/gopls/internal/template"
 	"golang.org/x/tools/internal/event"
 )
 
 func (s *server) SemanticTokensFull(ctx context.Context, params *protocol.SemanticTokensParams) (*protocol.SemanticTokens, error) {
-	return s.semanticTokens(ctx, params.TextDocument, nil)
+	tokens, err := s.semanticTokens(ctx, params.TextDocument, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.recordSemanticTokens(params.TextDocument.URI, tokens)
+	return tokens, nil
+}
+
+// SemanticTokensFullDelta implements textDocument/semanticTokens/full/delta.
+//
+// If the previous result ID is that of the most recent set of tokens for the
+// document, it returns a [protocol.SemanticTokensDelta] describing the
+// changes since then; otherwise, it returns the full set of tokens.
+func (s *server) SemanticTokensFullDelta(ctx context.Context, params *protocol.SemanticTokensDeltaParams) (any, error) {
+	tokens, err := s.semanticTokens(ctx, params.TextDocument, nil)
+	if err != nil {
+		return nil, err
+	}
+	prev, ok := s.recordSemanticTokens(params.TextDocument.URI, tokens)
+	if !ok || prev.resultID != params.PreviousResultID {
+		return tokens, nil
+	}
+	delta := &protocol.SemanticTokensDelta{
+		ResultID: tokens.ResultID,
+		Edits:    []protocol.SemanticTokensEdit{}, // non-nil
+	}
+	if start, deleteCount, insert := semtok.Diff(prev.data, tokens.Data); deleteCount > 0 || len(insert) > 0 {
+		delta.Edits = append(delta.Edits, protocol.SemanticTokensEdit{
+			Start:       uint32(start),
+			DeleteCount: uint32(deleteCount),
+			Data:        insert,
+		})
+	}
+	return delta, nil
+}
+
+// semanticTokensResult records the most recent full set of semantic
+// tokens returned for a document, so that later delta requests may be
+// answered with edits relative to it.
+type semanticTokensResult struct {
+	resultID string
+	data     []uint32
+}
+
+// recordSemanticTokens assigns a fresh result ID to the full set of
+// tokens for the given document, and records them as the latest result,
+// returning the previous one, if any.
+func (s *server) recordSemanticTokens(uri protocol.DocumentURI, tokens *protocol.SemanticTokens) (prev semanticTokensResult, ok bool) {
+	s.semanticTokensMu.Lock()
+	defer s.semanticTokensMu.Unlock()
+
+	s.semanticTokensSeq++
+	tokens.ResultID = strconv.FormatUint(s.semanticTokensSeq, 10)
+	if s.semanticTokenResults == nil {
+		s.semanticTokenResults = make(map[protocol.DocumentURI]semanticTokensResult)
+	}
+	prev, ok = s.semanticTokenResults[uri]
+	s.semanticTokenResults[uri] = semanticTokensResult{tokens.ResultID, tokens.Data}
+	return prev, ok
+}
+
+// forgetSemanticTokens discards the latest semantic tokens of a document.
+func (s *server) forgetSemanticTokens(uri protocol.DocumentURI) {
+	s.semanticTokensMu.Lock()
+	defer s.semanticTokensMu.Unlock()
+	delete(s.semanticTokenResults, uri)
 }
 
 func (s *server) SemanticTokensRange(ctx context.Context, params *protocol.SemanticTokensRangeParams) (*protocol.SemanticTokens, error) {
@@ -54,6 +54,7 @@ func New(session *cache.Session, client protocol.ClientCloser, options *settings
 		progress:            progress.NewTracker(client),
 		options:             options,
 		viewsToDiagnose:     make(map[*cache.View]uint64),
+		workspaceAnalysis:   make(map[*cache.View]workspaceAnalysis),
 	}
 }
 
@@ -118,6 +119,11 @@ type server struct {
 	diagnosticsMu sync.Mutex // guards map and its values
 	diagnostics   map[protocol.DocumentURI]*fileDiagnostics
 
+	// workspaceAnalysis memoizes, for each view, the analysis of the
+	// workspace packages without open files, for workspace/diagnostic.
+	workspaceAnalysisMu sync.Mutex
+	workspaceAnalysis   map[*cache.View]workspaceAnalysis
+
 	// diagnosticsSema limits the concurrency of diagnostics runs, which can be
 	// expensive.
 	diagnosticsSema chan unit
@@ -145,6 +151,12 @@ type server struct {
 	efficacyItems   []protocol.CompletionItem
 	efficacyPos     protocol.Position
 
+	// Track the most recent full semantic tokens of each document, for
+	// computing deltas. Entries are removed when the document is closed.
+	semanticTokensMu     sync.Mutex
+	semanticTokensSeq    uint64 // source of result IDs
+	semanticTokenResults map[protocol.DocumentURI]semanticTokensResult
+
 	// Web server (for package documentation, etc) associated with this
 	// LSP server. Opened on demand, and closed during LSP Shutdown.
 	webOnce sync.Once
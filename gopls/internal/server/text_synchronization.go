@@ -63,6 +63,10 @@ const (
 	// FromToggleCompilerOptDetails refers to state changes resulting from toggling
 	// a package's compiler optimization details flag.
 	FromToggleCompilerOptDetails
+
+	// FromCoverage refers to state changes resulting from showing or
+	// hiding the test coverage of a package.
+	FromCoverage
 )
 
 func (m ModificationSource) String() string {
@@ -85,6 +89,8 @@ func (m ModificationSource) String() string {
 		return "from check upgrades"
 	case FromResetGoModDiagnostics:
 		return "from resetting go.mod diagnostics"
+	case FromCoverage:
+		return "from showing coverage"
 	default:
 		return "unknown file modification"
 	}
@@ -204,6 +210,8 @@ func (s *server) DidClose(ctx context.Context, params *protocol.DidCloseTextDocu
 	ctx, done := event.Start(ctx, "server.DidClose", label.URI.Of(params.TextDocument.URI))
 	defer done()
 
+	s.forgetSemanticTokens(params.TextDocument.URI)
+
 	return s.didModifyFiles(ctx, FromDidClose, file.Modification{
 		URI:     params.TextDocument.URI,
 		Action:  file.Close,
@@ -88,34 +88,39 @@ const (
 	GoplsDocFeatures protocol.CodeActionKind = "gopls.doc.features"
 
 	// refactor.rewrite
-	RefactorRewriteChangeQuote        protocol.CodeActionKind = "refactor.rewrite.changeQuote"
-	RefactorRewriteFillStruct         protocol.CodeActionKind = "refactor.rewrite.fillStruct"
-	RefactorRewriteFillSwitch         protocol.CodeActionKind = "refactor.rewrite.fillSwitch"
-	RefactorRewriteInvertIf           protocol.CodeActionKind = "refactor.rewrite.invertIf"
-	RefactorRewriteJoinLines          protocol.CodeActionKind = "refactor.rewrite.joinLines"
-	RefactorRewriteRemoveUnusedParam  protocol.CodeActionKind = "refactor.rewrite.removeUnusedParam"
-	RefactorRewriteMoveParamLeft      protocol.CodeActionKind = "refactor.rewrite.moveParamLeft"
-	RefactorRewriteMoveParamRight     protocol.CodeActionKind = "refactor.rewrite.moveParamRight"
-	RefactorRewriteSplitLines         protocol.CodeActionKind = "refactor.rewrite.splitLines"
-	RefactorRewriteEliminateDotImport protocol.CodeActionKind = "refactor.rewrite.eliminateDotImport"
-	RefactorRewriteAddTags            protocol.CodeActionKind = "refactor.rewrite.addTags"
-	RefactorRewriteImplementInterface protocol.CodeActionKind = "refactor.rewrite.implementInterface"
-	RefactorRewriteRemoveTags         protocol.CodeActionKind = "refactor.rewrite.removeTags"
+	RefactorRewriteChangeQuote          protocol.CodeActionKind = "refactor.rewrite.changeQuote"
+	RefactorRewriteFillStruct           protocol.CodeActionKind = "refactor.rewrite.fillStruct"
+	RefactorRewriteFillSwitch           protocol.CodeActionKind = "refactor.rewrite.fillSwitch"
+	RefactorRewriteFunctionalOptions    protocol.CodeActionKind = "refactor.rewrite.functionalOptions"
+	RefactorRewriteInvertIf             protocol.CodeActionKind = "refactor.rewrite.invertIf"
+	RefactorRewriteIntroduceParamObject protocol.CodeActionKind = "refactor.rewrite.introduceParamObject"
+	RefactorRewriteJoinLines            protocol.CodeActionKind = "refactor.rewrite.joinLines"
+	RefactorRewriteRemoveUnusedParam    protocol.CodeActionKind = "refactor.rewrite.removeUnusedParam"
+	RefactorRewriteMoveParamLeft        protocol.CodeActionKind = "refactor.rewrite.moveParamLeft"
+	RefactorRewriteMoveParamRight       protocol.CodeActionKind = "refactor.rewrite.moveParamRight"
+	RefactorRewriteSplitLines           protocol.CodeActionKind = "refactor.rewrite.splitLines"
+	RefactorRewriteEliminateDotImport   protocol.CodeActionKind = "refactor.rewrite.eliminateDotImport"
+	RefactorRewriteAddTags              protocol.CodeActionKind = "refactor.rewrite.addTags"
+	RefactorRewriteImplementInterface   protocol.CodeActionKind = "refactor.rewrite.implementInterface"
+	RefactorRewriteRemoveTags           protocol.CodeActionKind = "refactor.rewrite.removeTags"
 
 	// refactor.inline
 	RefactorInlineCall     protocol.CodeActionKind = "refactor.inline.call"
+	RefactorInlineCallAll  protocol.CodeActionKind = "refactor.inline.call-all"
 	RefactorInlineVariable protocol.CodeActionKind = "refactor.inline.variable"
 
 	// refactor.extract
 	RefactorExtractConstant    protocol.CodeActionKind = "refactor.extract.constant"
 	RefactorExtractConstantAll protocol.CodeActionKind = "refactor.extract.constant-all"
 	RefactorExtractFunction    protocol.CodeActionKind = "refactor.extract.function"
+	RefactorExtractInterface   protocol.CodeActionKind = "refactor.extract.interface"
 	RefactorExtractMethod      protocol.CodeActionKind = "refactor.extract.method"
 	RefactorExtractVariable    protocol.CodeActionKind = "refactor.extract.variable"
 	RefactorExtractVariableAll protocol.CodeActionKind = "refactor.extract.variable-all"
 	RefactorExtractToNewFile   protocol.CodeActionKind = "refactor.extract.toNewFile"
 
 	// refactor.move
+	RefactorMoveDecl protocol.CodeActionKind = "refactor.move.moveDecl"
 	RefactorMoveType protocol.CodeActionKind = "refactor.move.moveType"
 
 	// Note: add new kinds to:
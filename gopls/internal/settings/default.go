@@ -47,32 +47,37 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 						// This should include specific leaves in the tree,
 						// (e.g. refactor.inline.call) not generic branches
 						// (e.g. refactor.inline or refactor).
-						protocol.SourceFixAll:             true,
-						protocol.SourceOrganizeImports:    true,
-						protocol.QuickFix:                 true,
-						GoAssembly:                        true,
-						GoDoc:                             true,
-						GoFreeSymbols:                     true,
-						GoSplitPackage:                    true,
-						GoplsDocFeatures:                  true,
-						RefactorRewriteChangeQuote:        true,
-						RefactorRewriteFillStruct:         true,
-						RefactorRewriteFillSwitch:         true,
-						RefactorRewriteImplementInterface: true,
-						RefactorRewriteInvertIf:           true,
-						RefactorRewriteJoinLines:          true,
-						RefactorRewriteRemoveUnusedParam:  true,
-						RefactorRewriteSplitLines:         true,
-						RefactorInlineCall:                true,
-						RefactorInlineVariable:            true,
-						RefactorExtractConstant:           true,
-						RefactorExtractConstantAll:        true,
-						RefactorExtractFunction:           true,
-						RefactorExtractMethod:             true,
-						RefactorExtractVariable:           true,
-						RefactorExtractVariableAll:        true,
-						RefactorExtractToNewFile:          true,
-						RefactorMoveType:                  true, // gated by MoveType setting, which is off by default
+						protocol.SourceFixAll:               true,
+						protocol.SourceOrganizeImports:      true,
+						protocol.QuickFix:                   true,
+						GoAssembly:                          true,
+						GoDoc:                               true,
+						GoFreeSymbols:                       true,
+						GoSplitPackage:                      true,
+						GoplsDocFeatures:                    true,
+						RefactorRewriteChangeQuote:          true,
+						RefactorRewriteFillStruct:           true,
+						RefactorRewriteFillSwitch:           true,
+						RefactorRewriteFunctionalOptions:    true,
+						RefactorRewriteImplementInterface:   true,
+						RefactorRewriteInvertIf:             true,
+						RefactorRewriteIntroduceParamObject: true,
+						RefactorRewriteJoinLines:            true,
+						RefactorRewriteRemoveUnusedParam:    true,
+						RefactorRewriteSplitLines:           true,
+						RefactorInlineCall:                  true,
+						RefactorInlineCallAll:               true,
+						RefactorInlineVariable:              true,
+						RefactorExtractConstant:             true,
+						RefactorExtractConstantAll:          true,
+						RefactorExtractFunction:             true,
+						RefactorExtractInterface:            true,
+						RefactorExtractMethod:               true,
+						RefactorExtractVariable:             true,
+						RefactorExtractVariableAll:          true,
+						RefactorExtractToNewFile:            true,
+						RefactorMoveDecl:                    true, // gated by MoveDecl setting, which is off by default
+						RefactorMoveType:                    true, // gated by MoveType setting, which is off by default
 						// Not GoTest: it must be explicit in CodeActionParams.Context.Only
 					},
 					file.Mod: {
@@ -104,6 +104,7 @@ type ClientOptions struct {
 	RelatedInformationSupported                bool
 	CompletionTags                             bool
 	CompletionDeprecated                       bool
+	CompletionResolveOptions                   []string
 	SupportedResourceOperations                []protocol.ResourceOperationKind
 	CodeActionResolveOptions                   []string
 	ShowDocumentSupported                      bool
@@ -274,6 +275,26 @@ type UIOptions struct {
 	// MoveType enables producing Move Type codeactions. The implementation
 	// is unfinished so we use this setting to gate its use.
 	MoveType bool `status:"experimental"`
+
+	// MoveDecl enables producing "Move declaration to another package"
+	// code actions. The implementation is unfinished so we use this
+	// setting to gate its use.
+	MoveDecl bool `status:"experimental"`
+
+	// AddTestBranchCases causes the "Add test for FUNC" code action to
+	// seed the table of the new test with a case for each return
+	// statement and switch case of the function, each commented with
+	// the branch conditions that lead to it.
+	AddTestBranchCases bool `status:"experimental"`
+
+	// DynamicCallHierarchy causes the call hierarchy to include the
+	// dynamic calls through interface methods and function values, as
+	// computed by variable type analysis (VTA) of the SSA form of the
+	// workspace packages. Such calls are marked "dynamic".
+	//
+	// Building SSA for the whole workspace is expensive, so this
+	// setting is disabled by default.
+	DynamicCallHierarchy bool `status:"experimental"`
 }
 
 // A CodeLensSource identifies an (algorithmic) source of code lenses.
@@ -351,6 +372,22 @@ const (
 	//   for an alternative approach.
 	CodeLensTest CodeLensSource = "test"
 
+	// Show test coverage
+	//
+	// This codelens source annotates the `package` clause of each
+	// `*_test.go` file with a command to run the tests of the
+	// package and show their coverage: the statements not covered
+	// are reported as diagnostics of information severity, and
+	// hovering over a function reports the percentage of its
+	// statements that are covered.
+	//
+	// While coverage is shown, the `package` clause of each file of
+	// the package is annotated with a command to hide it.
+	//
+	// This source is off by default for the same reasons as the
+	// `test` source.
+	CodeLensCoverage CodeLensSource = "coverage"
+
 	// Tidy go.mod file
 	//
 	// This codelens source annotates the `module` directive in a
@@ -641,6 +678,29 @@ const (
 	// functions such as `fmt.Println` are excluded from the
 	// check.
 	IgnoredError InlayHint = "ignoredError"
+
+	// HeapEscapes inlay hints for variables and values that the
+	// compiler allocates on the heap:
+	// ```go
+	// 	t« escapes to heap» := T{}
+	// 	fmt.Println(s« escapes to heap»)
+	// ```
+	// These hints are derived from the compiler's log of
+	// optimization decisions (see the "Show compiler optimization
+	// details" code action), so computing them requires running the
+	// compiler on the package, and they are not shown for packages
+	// that do not build.
+	HeapEscapes InlayHint = "heapEscapes"
+
+	// InlinedCalls inlay hints for calls that the compiler inlines:
+	// ```go
+	// 	n := small(1)« inlined»
+	// ```
+	// Like `heapEscapes`, these hints are derived from the compiler's
+	// log of optimization decisions. Only calls to functions declared
+	// in the same package are annotated, as the log does not record
+	// whether the functions of other packages can be inlined.
+	InlinedCalls InlayHint = "inlinedCalls"
 )
 
 type NavigationOptions struct {
@@ -1091,6 +1151,11 @@ func (o *Options) ForClientCapabilities(clientInfo *protocol.ClientInfo, caps pr
 		o.CompletionDeprecated = true
 	}
 
+	// Check which completion item properties the client can resolve lazily.
+	if rs := caps.TextDocument.Completion.CompletionItem.ResolveSupport; rs != nil {
+		o.CompletionResolveOptions = rs.Properties
+	}
+
 	// Check if the client supports code actions resolving.
 	if caps.TextDocument.CodeAction.DataSupport && caps.TextDocument.CodeAction.ResolveSupport != nil {
 		o.CodeActionResolveOptions = caps.TextDocument.CodeAction.ResolveSupport.Properties
@@ -1448,6 +1513,15 @@ func (o *Options) setOne(name string, value any) (applied []CounterPath, _ error
 	case "moveType":
 		return setBool(&o.MoveType, value)
 
+	case "moveDecl":
+		return setBool(&o.MoveDecl, value)
+
+	case "addTestBranchCases":
+		return setBool(&o.AddTestBranchCases, value)
+
+	case "dynamicCallHierarchy":
+		return setBool(&o.DynamicCallHierarchy, value)
+
 	// deprecated and renamed settings
 	//
 	// These should never be deleted: there is essentially no cost
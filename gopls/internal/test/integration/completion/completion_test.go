@@ -587,6 +587,99 @@ func doit() {
 	})
 }
 
+func TestResolveCompletionItem(t *testing.T) {
+	const files = `
+-- go.mod --
+module test.com
+
+go 1.16
+-- prog.go --
+package waste
+
+import "test.com/dep"
+
+// fooFunc returns false.
+//
+// Deprecated: use newFoof.
+func fooFunc() bool {
+	return false
+}
+
+func doit() {
+	if fooF
+	math.Sqr
+	dep.Ol
+}
+-- dep/dep.go --
+package dep
+
+// Old does nothing.
+//
+// Deprecated: don't use it.
+func Old() {}
+`
+	const capabilities = `{ "textDocument": { "completion": { "completionItem": {
+		"tagSupport": { "valueSet": [1] },
+		"resolveSupport": { "properties": ["documentation", "additionalTextEdits"] }
+	} } } }`
+	WithOptions(
+		CapabilitiesJSON([]byte(capabilities)),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("prog.go")
+
+		// The documentation of fooFunc is deferred, but not its deprecation.
+		loc := env.RegexpSearch("prog.go", "if fooF()")
+		completions := env.Completion(loc)
+		if diff := compareCompletionLabels([]string{"fooFunc"}, completions.Items); diff != "" {
+			t.Fatal(diff)
+		}
+		item := completions.Items[0]
+		if item.Documentation != nil || item.Data == nil {
+			t.Fatalf("completion item was resolved eagerly: %#v", item)
+		}
+		if !slices.Contains(item.Tags, protocol.ComplDeprecated) {
+			t.Errorf("unresolved tags = %v, want deprecation", item.Tags)
+		}
+		resolved := env.ResolveCompletionItem(item)
+		if resolved.Documentation == nil || !strings.Contains(fmt.Sprint(resolved.Documentation.Value), "fooFunc returns false.") {
+			t.Errorf("resolved documentation = %v, want doc comment of fooFunc", resolved.Documentation)
+		}
+		if !slices.Contains(resolved.Tags, protocol.ComplDeprecated) {
+			t.Errorf("resolved tags = %v, want deprecation", resolved.Tags)
+		}
+
+		// The deprecation of a function of another package is deferred too.
+		loc = env.RegexpSearch("prog.go", "dep.Ol()")
+		completions = env.Completion(loc)
+		if diff := compareCompletionLabels([]string{"Old"}, completions.Items); diff != "" {
+			t.Fatal(diff)
+		}
+		item = completions.Items[0]
+		if item.Documentation != nil || len(item.Tags) > 0 {
+			t.Fatalf("completion item was resolved eagerly: %#v", item)
+		}
+		resolved = env.ResolveCompletionItem(item)
+		if !slices.Contains(resolved.Tags, protocol.ComplDeprecated) {
+			t.Errorf("resolved tags = %v, want deprecation", resolved.Tags)
+		}
+
+		// The import of an unimported package is deferred.
+		loc = env.RegexpSearch("prog.go", "Sqr()")
+		completions = env.Completion(loc)
+		if len(completions.Items) == 0 {
+			t.Fatalf("no completion items")
+		}
+		item = completions.Items[0]
+		if len(item.AdditionalTextEdits) > 0 {
+			t.Fatalf("import edits were computed eagerly: %v", item.AdditionalTextEdits)
+		}
+		env.AcceptCompletion(loc, *env.ResolveCompletionItem(item))
+		if got := env.BufferText("prog.go"); !strings.Contains(got, `"math"`) {
+			t.Errorf("resolved completion did not add import:\n%s", got)
+		}
+	})
+}
+
 func TestUnimportedCompletion_VSCodeIssue1489(t *testing.T) {
 	const src = `
 -- go.mod --
@@ -159,6 +159,121 @@ func TestDiagnosticClearingOnEdit(t *testing.T) {
 	})
 }
 
+func TestWorkspaceDiagnostics(t *testing.T) {
+	WithOptions(
+		Settings{
+			"pullDiagnostics": true,
+		},
+	).Run(t, badPackage, func(t *testing.T, env *Env) {
+		// Collect the full reports, none of the files being open.
+		env.AfterChange()
+		reports := workspaceReports(env.WorkspaceDiagnostics())
+		var previous []protocol.PreviousResultID
+		for _, f := range []string{"a.go", "b.go"} {
+			uri := env.Sandbox.Workdir.URI(f)
+			report, ok := reports[uri]
+			if !ok {
+				t.Fatalf("workspace/diagnostic returned no report for %s", f)
+			}
+			if report.Kind != string(protocol.DiagnosticFull) || len(report.Items) != 1 {
+				t.Errorf("workspace/diagnostic(%s) returned %q report with %d diagnostics, want full report with 1. Got %v", f, report.Kind, len(report.Items), report.Items)
+			}
+			previous = append(previous, protocol.PreviousResultID{URI: uri, Value: report.ResultID})
+		}
+
+		// Nothing changed, so both reports are unchanged.
+		for uri, report := range workspaceReports(env.WorkspaceDiagnostics(previous...)) {
+			if report.Kind != string(protocol.DiagnosticUnchanged) {
+				t.Errorf("workspace/diagnostic(%s) with previous result IDs returned %q report, want unchanged", uri, report.Kind)
+			}
+		}
+
+		// Fix the error; both reports now change and are empty.
+		env.OpenFile("b.go")
+		env.RegexpReplace("b.go", "(A) = 2", "B")
+		env.AfterChange()
+		for uri, report := range workspaceReports(env.WorkspaceDiagnostics(previous...)) {
+			if report.Kind != string(protocol.DiagnosticFull) || len(report.Items) != 0 {
+				t.Errorf("workspace/diagnostic(%s) after edit returned %q report with %d diagnostics, want full report with 0. Got %v", uri, report.Kind, len(report.Items), report.Items)
+			}
+			if uri == env.Sandbox.Workdir.URI("b.go") {
+				if want := int32(env.Editor.BufferVersion("b.go")); report.Version != want {
+					t.Errorf("workspace/diagnostic(b.go) after edit returned version %d, want %d", report.Version, want)
+				}
+			}
+		}
+	})
+}
+
+// TestWorkspaceDiagnostics_Analysis checks that workspace/diagnostic
+// reports analyzer diagnostics for packages without open files, which
+// are not published.
+func TestWorkspaceDiagnostics_Analysis(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- a/a.go --
+package a
+
+import "fmt"
+
+func _() {
+	fmt.Printf("%d", "s")
+}
+-- b/b.go --
+package b
+`
+	WithOptions(
+		Settings{
+			"pullDiagnostics": true,
+		},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.AfterChange(NoDiagnostics(ForFile("a/a.go")))
+		reports := workspaceReports(env.WorkspaceDiagnostics())
+		report := reports[env.Sandbox.Workdir.URI("a/a.go")]
+		if len(report.Items) != 1 || report.Items[0].Source != "printf" {
+			t.Fatalf("workspace/diagnostic(a/a.go) returned %v, want one printf diagnostic", report.Items)
+		}
+		previous := []protocol.PreviousResultID{{URI: report.URI, Value: report.ResultID}}
+
+		// Opening the file publishes the same diagnostic, so the report
+		// is unchanged.
+		env.OpenFile("a/a.go")
+		env.AfterChange(Diagnostics(env.AtRegexp("a/a.go", "%d")))
+		reports = workspaceReports(env.WorkspaceDiagnostics(previous...))
+		if got := reports[report.URI].Kind; got != string(protocol.DiagnosticUnchanged) {
+			t.Errorf("workspace/diagnostic(a/a.go) after opening returned %q report, want unchanged", got)
+		}
+	})
+}
+
+// workspaceReports indexes the items of a workspace diagnostic report by URI.
+//
+// Unchanged reports are returned with empty Items. (The JSON decoding of
+// Or_WorkspaceDocumentDiagnosticReport cannot distinguish the two kinds, so
+// the Kind field, not the Go type, is authoritative.)
+func workspaceReports(report *protocol.WorkspaceDiagnosticReport) map[protocol.DocumentURI]protocol.WorkspaceFullDocumentDiagnosticReport {
+	reports := make(map[protocol.DocumentURI]protocol.WorkspaceFullDocumentDiagnosticReport)
+	for _, item := range report.Items {
+		switch item := item.Value.(type) {
+		case protocol.WorkspaceFullDocumentDiagnosticReport:
+			reports[item.URI] = item
+		case protocol.WorkspaceUnchangedDocumentDiagnosticReport:
+			reports[item.URI] = protocol.WorkspaceFullDocumentDiagnosticReport{
+				URI:     item.URI,
+				Version: item.Version,
+				FullDocumentDiagnosticReport: protocol.FullDocumentDiagnosticReport{
+					Kind:     item.Kind,
+					ResultID: item.ResultID,
+				},
+			}
+		}
+	}
+	return reports
+}
+
 func TestDiagnosticClearingOnDelete_Issue37049(t *testing.T) {
 	Run(t, badPackage, func(t *testing.T, env *Env) {
 		env.OpenFile("a.go")
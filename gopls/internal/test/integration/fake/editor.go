@@ -1069,6 +1069,27 @@ func (e *Editor) Diagnostics(ctx context.Context, path string) ([]protocol.Diagn
 	return report.Items, nil
 }
 
+// WorkspaceDiagnostics requests diagnostics for the entire workspace using
+// the workspace/diagnostic request, passing the given previous result IDs.
+func (e *Editor) WorkspaceDiagnostics(ctx context.Context, previous []protocol.PreviousResultID) (*protocol.WorkspaceDiagnosticReport, error) {
+	if e.Server == nil {
+		return nil, errors.New("not connected")
+	}
+	e.mu.Lock()
+	capabilities := e.serverCapabilities.DiagnosticProvider
+	e.mu.Unlock()
+
+	if capabilities == nil {
+		return nil, errors.New("server does not support pull diagnostics")
+	}
+	if opts, ok := capabilities.Value.(protocol.DiagnosticOptions); !ok || !opts.WorkspaceDiagnostics {
+		return nil, errors.New("server does not support workspace diagnostics")
+	}
+	return e.Server.DiagnosticWorkspace(ctx, &protocol.WorkspaceDiagnosticParams{
+		PreviousResultIds: previous,
+	})
+}
+
 // GetQuickFixes returns the available quick fix code actions.
 func (e *Editor) GetQuickFixes(ctx context.Context, loc protocol.Location, diagnostics []protocol.Diagnostic) ([]protocol.CodeAction, error) {
 	return e.CodeActions(ctx, loc, diagnostics, protocol.QuickFix, protocol.SourceFixAll)
@@ -1288,6 +1309,14 @@ func (e *Editor) Completion(ctx context.Context, loc protocol.Location) (*protoc
 	return completions, nil
 }
 
+// ResolveCompletionItem executes a completionItem/resolve request on the server.
+func (e *Editor) ResolveCompletionItem(ctx context.Context, item protocol.CompletionItem) (*protocol.CompletionItem, error) {
+	if e.Server == nil {
+		return nil, nil
+	}
+	return e.Server.ResolveCompletionItem(ctx, &item)
+}
+
 func (e *Editor) DidCreateFiles(ctx context.Context, files ...protocol.DocumentURI) error {
 	if e.Server == nil {
 		return nil
@@ -1476,6 +1505,29 @@ func (e *Editor) RenameFile(ctx context.Context, oldPath, newPath string) error
 	return nil
 }
 
+// WillRenameFile sends a workspace/willRenameFiles request for the renaming
+// of oldPath to newPath, and applies the resulting workspace edit, if any.
+// It does not perform the renaming itself: see [Editor.RenameFile].
+func (e *Editor) WillRenameFile(ctx context.Context, oldPath, newPath string) error {
+	if e.Server == nil {
+		return nil
+	}
+	params := &protocol.RenameFilesParams{
+		Files: []protocol.FileRename{{
+			OldURI: e.sandbox.Workdir.URI(oldPath),
+			NewURI: e.sandbox.Workdir.URI(newPath),
+		}},
+	}
+	wsedit, err := e.Server.WillRenameFiles(ctx, params)
+	if err != nil {
+		return err
+	}
+	if wsedit == nil {
+		return nil
+	}
+	return e.applyWorkspaceEdit(ctx, wsedit)
+}
+
 // renameBuffers renames in-memory buffers affected by the renaming of
 // oldPath->newPath, returning the resulting text documents that must be closed
 // and opened over the LSP.
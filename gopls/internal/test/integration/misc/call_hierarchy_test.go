@@ -34,3 +34,53 @@ package pkg
 		env.Editor.Server.PrepareCallHierarchy(env.Ctx, &params)
 	})
 }
+
+// TestDynamicCallHierarchy_IllTyped checks that the dynamic call
+// hierarchy logs the workspace packages with type errors, whose calls
+// it omits.
+func TestDynamicCallHierarchy_IllTyped(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a/a.go --
+package a
+
+func Each(f func()) {
+	f()
+}
+-- b/b.go --
+package b
+
+import "mod.com/a"
+
+func g() {}
+
+func use() {
+	a.Each(g)
+	var _ int = "oops"
+}
+`
+	WithOptions(
+		Settings{"dynamicCallHierarchy": true},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		loc := env.RegexpSearch("a/a.go", "Each")
+
+		var params protocol.CallHierarchyPrepareParams
+		params.TextDocument.URI = loc.URI
+		params.Position = loc.Range.Start
+		items, err := env.Editor.Server.PrepareCallHierarchy(env.Ctx, &params)
+		if err != nil || len(items) != 1 {
+			t.Fatalf("PrepareCallHierarchy = %v, %v", items, err)
+		}
+		for range 2 {
+			if _, err := env.Editor.Server.OutgoingCalls(env.Ctx, &protocol.CallHierarchyOutgoingCallsParams{Item: items[0]}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// The graph is built, and the package logged, once per snapshot.
+		env.Await(LogMatching(protocol.Info, `omits the calls within packages with type errors: \[mod.com/b\]`, 1, false))
+	})
+}
@@ -75,6 +75,7 @@ func main(){
 			settings.GoSplitPackage,
 			settings.GoToggleCompilerOptDetails,
 			settings.RefactorInlineCall,
+			settings.RefactorInlineCallAll,
 			settings.GoplsDocFeatures,
 		})
 
@@ -0,0 +1,124 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/server"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+const coverageMod = `
+-- go.mod --
+module mod.com
+
+go 1.18
+
+-- a/a.go --
+package a
+
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+-- a/a_test.go --
+package a
+
+import "testing"
+
+func TestAbs(t *testing.T) {
+	if Abs(1) != 1 {
+		t.Fatal("Abs(1) != 1")
+	}
+}
+`
+
+// TestCoverage exercises the "run tests with coverage" code lens.
+func TestCoverage(t *testing.T) {
+	WithOptions(
+		Settings{"codelenses": map[string]bool{"coverage": true}},
+	).Run(t, coverageMod, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		env.OpenFile("a/a_test.go")
+		env.ExecuteCodeLensCommand("a/a_test.go", command.Coverage, nil)
+		env.Await(CompletedWork(server.DiagnosticWorkTitle(server.FromCoverage), 1, true))
+		env.AfterChange(
+			Diagnostics(
+				ForFile("a/a.go"),
+				AtPosition("a/a.go", 4, 2), // (LSP coordinates)
+				WithMessage("1 statement not covered by tests"),
+				WithSeverityTags("coverage", protocol.SeverityInformation, nil),
+			),
+		)
+
+		content, _ := env.Hover(env.RegexpSearch("a/a.go", "Abs"))
+		if want := "Test coverage: 66.7% of statements"; content == nil || !strings.Contains(content.Value, want) {
+			t.Errorf("hover over Abs = %v, want containing %q", content, want)
+		}
+
+		// The code lens now offers to hide the coverage.
+		var hide *protocol.Command
+		for _, lens := range env.CodeLens("a/a.go") {
+			if lens.Command.Title == "hide coverage" {
+				hide = lens.Command
+			}
+		}
+		if hide == nil {
+			t.Fatal("no 'hide coverage' code lens")
+		}
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   hide.Command,
+			Arguments: hide.Arguments,
+		}, nil)
+		env.Await(CompletedWork(server.DiagnosticWorkTitle(server.FromCoverage), 2, true))
+		env.AfterChange(NoDiagnostics(ForFile("a/a.go")))
+	})
+}
+
+// TestCoverage_profile exercises the coverage overlay of an existing
+// profile, and its update when the profile changes.
+func TestCoverage_profile(t *testing.T) {
+	const uncovered = `mode: set
+mod.com/a/a.go:3.21,4.10 1 1
+mod.com/a/a.go:7.2,7.10 1 1
+mod.com/a/a.go:4.10,6.3 1 0
+`
+	Run(t, coverageMod, func(t *testing.T, env *Env) {
+		env.WriteWorkspaceFile("cover.out", uncovered)
+		env.OpenFile("a/a.go")
+		cmd := command.NewCoverageCommand("", command.CoverageArgs{
+			URI:     env.Sandbox.Workdir.URI("a/a.go"),
+			Profile: env.Sandbox.Workdir.URI("cover.out"),
+		})
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, nil)
+		env.Await(
+			Diagnostics(
+				ForFile("a/a.go"),
+				AtPosition("a/a.go", 3, 9),
+				WithMessage("1 statement not covered by tests"),
+			),
+		)
+
+		// Coverage is not reported for files with unsaved changes.
+		env.RegexpReplace("a/a.go", "return x", "return +x")
+		env.AfterChange(NoDiagnostics(ForFile("a/a.go")))
+		env.SaveBuffer("a/a.go")
+		env.AfterChange(Diagnostics(ForFile("a/a.go"), WithMessage("not covered")))
+
+		// The overlay follows changes to the profile.
+		env.WriteWorkspaceFile("cover.out", strings.ReplaceAll(uncovered, " 0\n", " 1\n"))
+		env.AfterChange(NoDiagnostics(ForFile("a/a.go")))
+	})
+}
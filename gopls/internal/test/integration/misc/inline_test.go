@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+// TestInlineAllCalls_preview checks that the preview mode of the
+// InlineAllCalls command reports the inlining of calls to a function of
+// a dependency as a unified diff, without applying it, and reports the
+// calls within the dependency, which it cannot inline.
+func TestInlineAllCalls_preview(t *testing.T) {
+	const proxy = `
+-- example.com/dep@v1.2.0/go.mod --
+module example.com/dep
+
+go 1.18
+-- example.com/dep@v1.2.0/dep.go --
+package dep
+
+import "strings"
+
+// Deprecated: use strings.ToUpper.
+func Upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+func Shout(s string) string {
+	return Upper(s) + "!"
+}
+`
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+
+require example.com/dep v1.2.0
+-- a/a.go --
+package a
+
+import "example.com/dep"
+
+var X = dep.Upper("x")
+`
+	WithOptions(
+		ProxyFiles(proxy),
+		WriteGoSum("."),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		before := env.BufferText("a/a.go")
+		cmd := command.NewInlineAllCallsCommand("", command.InlineAllCallsArgs{
+			Location: env.RegexpSearch("a/a.go", "Upper"),
+			Preview:  true,
+		})
+		var result command.InlineAllCallsResult
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, &result)
+		for _, want := range []string{
+			`-import "example.com/dep"`,
+			`+import "strings"`,
+			`-var X = dep.Upper("x")`,
+			`+var X = strings.ToUpper("x")`,
+		} {
+			if !strings.Contains(result.Diff, want) {
+				t.Errorf("InlineAllCalls diff does not contain %q:\n%s", want, result.Diff)
+			}
+		}
+		if len(result.Skipped) != 1 || !strings.HasSuffix(string(result.Skipped[0].URI), "/dep.go") {
+			t.Errorf("InlineAllCalls skipped %v, want the call in dep.go", result.Skipped)
+		}
+		if got := env.BufferText("a/a.go"); got != before {
+			t.Errorf("InlineAllCalls preview modified a/a.go:\n%s", got)
+		}
+	})
+}
@@ -444,6 +444,141 @@ package b
 	})
 }
 
+func TestWillRenameFiles_Directory(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- lib/a.go --
+package lib
+
+const A = 1
+
+-- lib/a_test.go --
+package lib_test
+
+import "mod.com/lib"
+
+var _ = lib.A
+
+-- lib/nested/b.go --
+package nested
+
+const B = 2
+
+-- main.go --
+package main
+
+import (
+	"mod.com/lib"
+	"mod.com/lib/nested"
+)
+
+func main() {
+	println(lib.A, nested.B)
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.WillRenameFile("lib", "lib2")
+		env.RenameFile("lib", "lib2")
+		env.AfterChange(NoDiagnostics())
+
+		env.RegexpSearch("lib2/a.go", "package lib2")
+		env.RegexpSearch("lib2/a_test.go", "package lib2_test")
+		env.RegexpSearch("lib2/a_test.go", `"mod.com/lib2"`)
+		env.RegexpSearch("lib2/a_test.go", `lib2\.A`)
+		env.RegexpSearch("lib2/nested/b.go", "package nested")
+		env.RegexpSearch("main.go", `"mod.com/lib2"`)
+		env.RegexpSearch("main.go", `"mod.com/lib2/nested"`)
+		env.RegexpSearch("main.go", `lib2\.A`)
+	})
+}
+
+// TestWillRenameFiles_NestedModule checks that moving a directory that
+// contains both packages of the enclosing module and a nested module
+// updates the imports of the former, and leaves the latter alone.
+func TestWillRenameFiles_NestedModule(t *testing.T) {
+	const files = `
+-- go.work --
+go 1.18
+
+use (
+	.
+	./lib/sub
+)
+-- go.mod --
+module mod.com
+
+go 1.18
+-- lib/a.go --
+package lib
+
+const A = 1
+
+-- lib/sub/go.mod --
+module mod.com/lib/sub
+
+go 1.18
+-- lib/sub/b.go --
+package sub
+
+const B = 2
+
+-- main.go --
+package main
+
+import "mod.com/lib"
+
+func main() {
+	println(lib.A)
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.WillRenameFile("lib", "lib2")
+		env.RenameFile("lib", "lib2")
+
+		env.RegexpSearch("lib2/a.go", "package lib2")
+		env.RegexpSearch("main.go", `"mod.com/lib2"`)
+		env.RegexpSearch("main.go", `lib2\.A`)
+		env.RegexpSearch("lib2/sub/go.mod", `module mod.com/lib/sub`)
+		env.RegexpSearch("lib2/sub/b.go", "package sub")
+	})
+}
+
+func TestWillRenameFiles_GoFile(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- a/a.go --
+package a
+
+const A = 1
+
+-- a/x.go --
+package a
+
+const X = 2
+
+-- b/b.go --
+package b
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		// Renaming within a directory requires no edits.
+		env.WillRenameFile("a/x.go", "a/y.go")
+		if env.Editor.HasBuffer("a/x.go") {
+			t.Errorf("willRenameFiles within a package edited a/x.go")
+		}
+
+		env.WillRenameFile("a/x.go", "b/x.go")
+		env.RenameFile("a/x.go", "b/x.go")
+		env.AfterChange(NoDiagnostics())
+		env.RegexpSearch("b/x.go", "package b")
+	})
+}
+
 func TestRenamePackage_Tests(t *testing.T) {
 	const files = `
 -- go.mod --
@@ -5,7 +5,9 @@
 package misc
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
@@ -319,3 +321,90 @@ package p
 		}
 	})
 }
+
+func TestSemanticTokensFullDelta(t *testing.T) {
+	const src = `
+-- go.mod --
+module example.com
+go 1.21
+
+-- p.go --
+package p
+
+func F() {
+	x := 1
+	_ = x
+}
+
+func G() {}
+`
+	WithOptions(
+		Modes(Default),
+		Settings{"semanticTokens": true},
+	).Run(t, src, func(t *testing.T, env *Env) {
+		env.OpenFile("p.go")
+		td := env.Editor.TextDocumentIdentifier("p.go")
+		full, err := env.Editor.Server.SemanticTokensFull(env.Ctx, &protocol.SemanticTokensParams{TextDocument: td})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if full.ResultID == "" {
+			t.Fatal("SemanticTokensFull returned no result ID")
+		}
+
+		// Request a delta after an edit; applying it to the previous
+		// tokens must yield the current full set of tokens.
+		env.RegexpReplace("p.go", "x := 1", "x, y := 1, 2\n\t_ = y")
+		delta := semanticTokensDelta(t, env, td, full.ResultID)
+		if delta.Data != nil || len(delta.Edits) != 1 {
+			t.Fatalf("SemanticTokensFullDelta returned data %v and %d edits, want one edit", delta.Data, len(delta.Edits))
+		}
+		edit := delta.Edits[0]
+		got := slices.Concat(full.Data[:edit.Start], edit.Data, full.Data[edit.Start+edit.DeleteCount:])
+		want, err := env.Editor.Server.SemanticTokensFull(env.Ctx, &protocol.SemanticTokensParams{TextDocument: td})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want.Data, got); diff != "" {
+			t.Errorf("tokens after applying delta do not match (-want +got):\n%s", diff)
+		}
+		// The edit must not cover the unaffected tokens of G.
+		if n := len(full.Data) - int(edit.Start+edit.DeleteCount); n < 5 {
+			t.Errorf("edit retains only %d trailing elements, want at least 5", n)
+		}
+
+		// A stale result ID yields the full set of tokens.
+		stale := semanticTokensDelta(t, env, td, full.ResultID)
+		if stale.Edits != nil || stale.ResultID == "" {
+			t.Errorf("SemanticTokensFullDelta with stale result ID returned edits %v, want full tokens", stale.Edits)
+		}
+		if diff := cmp.Diff(want.Data, stale.Data); diff != "" {
+			t.Errorf("full tokens for stale result ID do not match (-want +got):\n%s", diff)
+		}
+	})
+}
+
+// semanticTokensDelta invokes textDocument/semanticTokens/full/delta and
+// decodes its result, which is either full tokens or a delta.
+func semanticTokensDelta(t *testing.T, env *Env, td protocol.TextDocumentIdentifier, prev string) (res struct {
+	ResultID string                        `json:"resultId"`
+	Data     []uint32                      `json:"data"`
+	Edits    []protocol.SemanticTokensEdit `json:"edits"`
+}) {
+	t.Helper()
+	resp, err := env.Editor.Server.SemanticTokensFullDelta(env.Ctx, &protocol.SemanticTokensDeltaParams{
+		TextDocument:     td,
+		PreviousResultID: prev,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
@@ -262,6 +262,17 @@ func (e *Env) Diagnostics(name string) []protocol.Diagnostic {
 	return diags
 }
 
+// WorkspaceDiagnostics returns the workspace/diagnostic report for the
+// workspace, calling t.Fatal on any error.
+func (e *Env) WorkspaceDiagnostics(previous ...protocol.PreviousResultID) *protocol.WorkspaceDiagnosticReport {
+	e.TB.Helper()
+	report, err := e.Editor.WorkspaceDiagnostics(e.Ctx, previous)
+	if err != nil {
+		e.TB.Fatal(err)
+	}
+	return report
+}
+
 // GetQuickFixes returns the available quick fix code actions, calling t.Fatal
 // on any error.
 func (e *Env) GetQuickFixes(path string, diagnostics []protocol.Diagnostic) []protocol.CodeAction {
@@ -517,6 +528,14 @@ func (e *Env) RenameFile(oldPath, newPath string) {
 	}
 }
 
+// WillRenameFile wraps Editor.WillRenameFile, calling t.Fatal on any error.
+func (e *Env) WillRenameFile(oldPath, newPath string) {
+	e.TB.Helper()
+	if err := e.Editor.WillRenameFile(e.Ctx, oldPath, newPath); err != nil {
+		e.TB.Fatal(err)
+	}
+}
+
 // SignatureHelp wraps Editor.SignatureHelp, calling t.Fatal on error
 func (e *Env) SignatureHelp(loc protocol.Location) *protocol.SignatureHelp {
 	e.TB.Helper()
@@ -537,6 +556,17 @@ func (e *Env) Completion(loc protocol.Location) *protocol.CompletionList {
 	return completions
 }
 
+// ResolveCompletionItem resolves the deferred properties of a
+// completion item, calling t.Fatal on any error.
+func (e *Env) ResolveCompletionItem(item protocol.CompletionItem) *protocol.CompletionItem {
+	e.TB.Helper()
+	resolved, err := e.Editor.ResolveCompletionItem(e.Ctx, item)
+	if err != nil {
+		e.TB.Fatal(err)
+	}
+	return resolved
+}
+
 func (e *Env) DidCreateFiles(files ...protocol.DocumentURI) {
 	e.TB.Helper()
 	err := e.Editor.DidCreateFiles(e.Ctx, files...)
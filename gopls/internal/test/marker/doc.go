@@ -188,6 +188,10 @@ Here is the list of supported action markers:
     additional fields (source="compiler", kind="error"). Restore them using
     optional named arguments.
 
+  - decl(src, want ...location): performs a textDocument/declaration request
+    at the src location, and checks that the resulting set of locations
+    matches want.
+
   - def(src, want ...location): performs a textDocument/definition request at
     the src location, and checks that the resulting set of locations matches want.
 
@@ -237,6 +241,25 @@ Here is the list of supported action markers:
     (These locations are the declarations of the functions enclosing
     the calls, not the calls themselves.)
 
+  - inlinevalues(stopped location, want ...location): makes a
+    textDocument/inlineValue query for the whole file, as if a debugger had
+    stopped at the given location, and checks that the set of variable
+    lookups matches want.
+
+  - linkededits(src location, want ...location): makes a
+    textDocument/linkedEditingRange query at the src location, and checks
+    that the set of linked ranges matches want. With no want locations, it
+    checks that the result is empty.
+
+  - moniker(src location, want ...string): makes a textDocument/moniker
+    query at the src location, and checks that the kind and identifier of
+    each resulting moniker, separated by a space, match want.
+
+  - ontypeformat(src location, ch string, golden): makes a
+    textDocument/onTypeFormatting request at the end of src, as if the
+    character ch had just been typed before it, and compares the
+    file with the resulting edits applied against the named golden file.
+
   - outgoingcalls(src location, want ...location): makes a
     callHierarchy/outgoingCalls query at the src location, and checks that
     the set of call.To locations matches want.
@@ -258,6 +281,12 @@ Here is the list of supported action markers:
     (Failures in the computation to offer a fix do not generally result
     in LSP errors, so this marker is not appropriate for testing them.)
 
+  - rangeformat(golden, ranges ...location): makes a
+    textDocument/rangeFormatting request for the single given range, or a
+    textDocument/rangesFormatting request for several, and compares the
+    file with the resulting edits applied against the named golden file.
+    If the request fails, the golden file must contain the error message.
+
   - rank(location, ...string OR completionItem): executes a
     textDocument/completion request at the given location, and verifies that
     each expected completion item occurs in the results, in the expected order.
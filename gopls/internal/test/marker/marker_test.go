@@ -608,6 +608,7 @@ var actionMarkerFuncs = map[string]func(marker){
 	"codeaction":       actionMarkerFunc(codeActionMarker, "end", "diag", "action", "result", "edit", "err", "answers"),
 	"codelenses":       actionMarkerFunc(codeLensesMarker),
 	"complete":         actionMarkerFunc(completeMarker),
+	"decl":             actionMarkerFunc(declMarker),
 	"def":              actionMarkerFunc(defMarker),
 	"diag":             actionMarkerFunc(diagMarker, "exact"),
 	"documentlink":     actionMarkerFunc(documentLinkMarker),
@@ -620,7 +621,12 @@ var actionMarkerFuncs = map[string]func(marker){
 	"implementation":   actionMarkerFunc(implementationMarker, "err"),
 	"incomingcalls":    actionMarkerFunc(incomingCallsMarker),
 	"inlayhints":       actionMarkerFunc(inlayhintsMarker),
+	"inlinevalues":     actionMarkerFunc(inlineValuesMarker),
+	"linkededits":      actionMarkerFunc(linkedEditsMarker),
+	"moniker":          actionMarkerFunc(monikerMarker),
+	"ontypeformat":     actionMarkerFunc(onTypeFormatMarker),
 	"outgoingcalls":    actionMarkerFunc(outgoingCallsMarker),
+	"rangeformat":      actionMarkerFunc(rangeFormatMarker),
 	"preparerename":    actionMarkerFunc(prepareRenameMarker, "span"),
 	"rank":             actionMarkerFunc(rankMarker),
 	"refs":             actionMarkerFunc(refsMarker),
@@ -1751,6 +1757,24 @@ func defMarker(mark marker, loc protocol.Location, want ...protocol.Location) {
 	}
 }
 
+// declMarker implements the @decl marker.
+func declMarker(mark marker, loc protocol.Location, want ...protocol.Location) {
+	res, err := mark.server().Declaration(mark.ctx(), &protocol.DeclarationParams{
+		TextDocumentPositionParams: protocol.LocationTextDocumentPositionParams(loc),
+	})
+	if err != nil {
+		mark.errorf("declaration request failed: %v", err)
+		return
+	}
+	var got []protocol.Location
+	if res != nil {
+		got, _ = res.Value.(protocol.Declaration)
+	}
+	if err := compareLocations(mark, got, want); err != nil {
+		mark.errorf("decl failed: %v", err)
+	}
+}
+
 // typedefMarker implements the @typedef marker.
 func typedefMarker(mark marker, loc protocol.Location, want ...protocol.Location) {
 	wantErr := namedArgFunc(mark, "err", convertStringMatcher, stringMatcher{})
@@ -1839,6 +1863,59 @@ func formatMarker(mark marker, golden *Golden) {
 	compareGolden(mark, got, golden)
 }
 
+// rangeFormatMarker implements the @rangeformat marker.
+func rangeFormatMarker(mark marker, golden *Golden, locs ...protocol.Location) {
+	var (
+		edits []protocol.TextEdit
+		err   error
+	)
+	if len(locs) == 1 {
+		edits, err = mark.server().RangeFormatting(mark.ctx(), &protocol.DocumentRangeFormattingParams{
+			TextDocument: mark.document(),
+			Range:        locs[0].Range,
+		})
+	} else {
+		var rngs []protocol.Range
+		for _, loc := range locs {
+			rngs = append(rngs, loc.Range)
+		}
+		edits, err = mark.server().RangesFormatting(mark.ctx(), &protocol.DocumentRangesFormattingParams{
+			TextDocument: mark.document(),
+			Ranges:       rngs,
+		})
+	}
+	var got []byte
+	if err != nil {
+		got = []byte(err.Error() + "\n") // all golden content is newline terminated
+	} else {
+		got, _, err = protocol.ApplyEdits(mark.mapper(), edits)
+		if err != nil {
+			mark.errorf("ApplyEdits failed: %v", err)
+			return
+		}
+	}
+	compareGolden(mark, got, golden)
+}
+
+// onTypeFormatMarker implements the @ontypeformat marker.
+func onTypeFormatMarker(mark marker, loc protocol.Location, ch string, golden *Golden) {
+	edits, err := mark.server().OnTypeFormatting(mark.ctx(), &protocol.DocumentOnTypeFormattingParams{
+		TextDocument: mark.document(),
+		Position:     loc.Range.End,
+		Ch:           ch,
+	})
+	if err != nil {
+		mark.errorf("OnTypeFormatting failed: %v", err)
+		return
+	}
+	got, _, err := protocol.ApplyEdits(mark.mapper(), edits)
+	if err != nil {
+		mark.errorf("ApplyEdits failed: %v", err)
+		return
+	}
+	compareGolden(mark, got, golden)
+}
+
 func highlightLocationMarker(mark marker, loc protocol.Location, kindName expect.Identifier) protocol.DocumentHighlight {
 	var kind protocol.DocumentHighlightKind
 	switch kindName {
@@ -2379,6 +2456,82 @@ func implementationMarker(mark marker, src protocol.Location, want ...protocol.L
 	}
 }
 
+// inlineValuesMarker implements the @inlinevalues marker.
+func inlineValuesMarker(mark marker, stopped protocol.Location, want ...protocol.Location) {
+	m := mark.mapper()
+	all, err := m.OffsetRange(0, len(m.Content))
+	if err != nil {
+		mark.errorf("OffsetRange failed: %v", err)
+		return
+	}
+	values, err := mark.server().InlineValue(mark.ctx(), &protocol.InlineValueParams{
+		TextDocument: mark.document(),
+		Range:        all,
+		Context:      protocol.InlineValueContext{StoppedLocation: stopped.Range},
+	})
+	if err != nil {
+		mark.errorf("inlineValue at %s failed: %v", stopped, err)
+		return
+	}
+	var got []protocol.Location
+	for _, v := range values {
+		// The variants of InlineValue are not distinguished by
+		// Or_InlineValue.UnmarshalJSON, so the client side of the
+		// connection loses all but the range of each value.
+		data, err := json.Marshal(v)
+		if err != nil {
+			mark.errorf("marshaling inline value: %v", err)
+			return
+		}
+		var lookup struct{ Range protocol.Range }
+		if err := json.Unmarshal(data, &lookup); err != nil {
+			mark.errorf("unmarshaling inline value: %v", err)
+			return
+		}
+		got = append(got, stopped.URI.Location(lookup.Range))
+	}
+	if err := compareLocations(mark, got, want); err != nil {
+		mark.errorf("inlinevalues: %v", err)
+	}
+}
+
+// linkedEditsMarker implements the @linkededits marker.
+func linkedEditsMarker(mark marker, src protocol.Location, want ...protocol.Location) {
+	got, err := mark.server().LinkedEditingRange(mark.ctx(), &protocol.LinkedEditingRangeParams{
+		TextDocumentPositionParams: protocol.LocationTextDocumentPositionParams(src),
+	})
+	if err != nil {
+		mark.errorf("linkedEditingRange at %s failed: %v", src, err)
+		return
+	}
+	var gotLocs []protocol.Location
+	if got != nil {
+		for _, rng := range got.Ranges {
+			gotLocs = append(gotLocs, src.URI.Location(rng))
+		}
+	}
+	if err := compareLocations(mark, gotLocs, want); err != nil {
+		mark.errorf("linkededits: %v", err)
+	}
+}
+
+func monikerMarker(mark marker, src protocol.Location, want ...string) {
+	monikers, err := mark.server().Moniker(mark.ctx(), &protocol.MonikerParams{
+		TextDocumentPositionParams: protocol.LocationTextDocumentPositionParams(src),
+	})
+	if err != nil {
+		mark.errorf("moniker at %s failed: %v", src, err)
+		return
+	}
+	var got []string
+	for _, m := range monikers {
+		got = append(got, fmt.Sprintf("%s %s", *m.Kind, m.Identifier))
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		mark.errorf("moniker: unexpected result (-want +got):\n%s", diff)
+	}
+}
+
 func mcpToolMarker(mark marker, tool string, rawArgs string) {
 	if !mark.run.test.mcp {
 		mark.errorf("mcp not enabled: add -mcp")
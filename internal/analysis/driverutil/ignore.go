@@ -0,0 +1,181 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package driverutil
+
+// This file defines the suppression of diagnostics by //lint:ignore
+// directives, common to all drivers.
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ParseIgnoreDirective parses the text of a comment of the form
+//
+//	//lint:ignore analyzer[,analyzer...] reason
+//
+// which suppresses the diagnostics of the named analyzers. It reports
+// ok=false if the comment is not such a directive, and a non-nil error
+// if the directive is malformed.
+func ParseIgnoreDirective(text string) (names []string, reason string, ok bool, err error) {
+	rest, ok := strings.CutPrefix(text, "//lint:ignore")
+	if !ok {
+		return nil, "", false, nil
+	}
+	if r, _ := utf8.DecodeRuneInString(rest); rest != "" && !unicode.IsSpace(r) {
+		return nil, "", false, nil // e.g. //lint:ignored
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return nil, "", true, fmt.Errorf("//lint:ignore directive lacks an analyzer name")
+	}
+	names = strings.Split(fields[0], ",")
+	if slices.Contains(names, "") {
+		return nil, "", true, fmt.Errorf("//lint:ignore directive has an empty analyzer name")
+	}
+	if len(fields) == 1 {
+		return nil, "", true, fmt.Errorf("//lint:ignore directive lacks a reason")
+	}
+	return names, strings.Join(fields[1:], " "), true, nil
+}
+
+// An ignoreDirective is a well-formed //lint:ignore comment.
+type ignoreDirective struct {
+	comment    *ast.Comment
+	start, end token.Pos // extent of the suppressed source
+}
+
+// ignoreDirectives returns the well-formed //lint:ignore directives of
+// the specified files that name the analyzer.
+//
+// A directive in the doc comment of a declaration, spec, or field
+// suppresses diagnostics anywhere within it. A directive that follows
+// code on its line suppresses diagnostics on that line only; any other
+// directive suppresses diagnostics on its own line and on the following
+// line.
+func ignoreDirectives(fset *token.FileSet, files []*ast.File, analyzer string) []*ignoreDirective {
+	var directives []*ignoreDirective
+	for _, f := range files {
+		var docs map[*ast.CommentGroup]ast.Node // lazily populated
+		for _, group := range f.Comments {
+			for _, c := range group.List {
+				names, _, ok, err := ParseIgnoreDirective(c.Text)
+				if !ok || err != nil || !slices.Contains(names, analyzer) {
+					continue
+				}
+				if docs == nil {
+					docs = docComments(f)
+				}
+				d := &ignoreDirective{comment: c}
+				if n, ok := docs[group]; ok {
+					d.start, d.end = n.Pos(), n.End()
+				} else {
+					tok := fset.File(c.Pos())
+					line := tok.Line(c.Pos())
+					d.start = tok.LineStart(line)
+					if !followsCode(f, d.start, c) {
+						line++ // a directive on a line of its own also covers the next line
+					}
+					d.end = token.Pos(tok.Base() + tok.Size())
+					if line+1 <= tok.LineCount() {
+						d.end = tok.LineStart(line + 1)
+					}
+				}
+				directives = append(directives, d)
+			}
+		}
+	}
+	return directives
+}
+
+// followsCode reports whether comment c, which starts on the line at
+// lineStart, is preceded on that line by a token of file f.
+func followsCode(f *ast.File, lineStart token.Pos, c *ast.Comment) bool {
+	found := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.CommentGroup, *ast.Comment:
+			return false
+		}
+		if found || n.End() <= lineStart || n.Pos() >= c.Pos() {
+			return false // done, or disjoint from the line before c
+		}
+		if n.Pos() >= lineStart || n.End() <= c.Pos() {
+			found = true // n starts or ends on the line before c
+			return false
+		}
+		return true // n spans c's line; inspect its children
+	})
+	return found
+}
+
+// docComments returns a mapping from each doc comment of f to the
+// declaration, spec, or field that it documents.
+func docComments(f *ast.File) map[*ast.CommentGroup]ast.Node {
+	docs := make(map[*ast.CommentGroup]ast.Node)
+	ast.Inspect(f, func(n ast.Node) bool {
+		var doc *ast.CommentGroup
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			doc = n.Doc
+		case *ast.GenDecl:
+			doc = n.Doc
+		case *ast.TypeSpec:
+			doc = n.Doc
+		case *ast.ValueSpec:
+			doc = n.Doc
+		case *ast.ImportSpec:
+			doc = n.Doc
+		case *ast.Field:
+			doc = n.Doc
+		}
+		if doc != nil {
+			docs[doc] = n
+		}
+		return true
+	})
+	return docs
+}
+
+// SuppressDiagnostics returns the diagnostics reported by analyzer a
+// for the package of the specified files, less those suppressed by a
+// //lint:ignore directive in the files that names the analyzer,
+// followed by a diagnostic for each such directive that suppressed
+// none of them.
+func SuppressDiagnostics(fset *token.FileSet, files []*ast.File, a *analysis.Analyzer, diags []analysis.Diagnostic) []analysis.Diagnostic {
+	directives := ignoreDirectives(fset, files, a.Name)
+	if directives == nil {
+		return diags // common case
+	}
+	used := make(map[*ignoreDirective]bool)
+	var result []analysis.Diagnostic
+outer:
+	for _, diag := range diags {
+		for _, d := range directives {
+			if d.start <= diag.Pos && diag.Pos < d.end {
+				used[d] = true
+				continue outer // suppressed
+			}
+		}
+		result = append(result, diag)
+	}
+	for _, d := range directives {
+		if !used[d] {
+			result = append(result, analysis.Diagnostic{
+				Pos:     d.comment.Pos(),
+				End:     d.comment.End(),
+				Message: fmt.Sprintf("unused //lint:ignore directive for %s", a.Name),
+			})
+		}
+	}
+	return result
+}
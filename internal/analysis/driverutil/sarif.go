@@ -0,0 +1,302 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package driverutil
+
+// This file defines output of analysis results in the Static Analysis
+// Results Interchange Format (SARIF), version 2.1.0:
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// A SARIFLog accumulates analysis results for printing as a SARIF log
+// containing one run per analyzer. The zero value is ready to use.
+type SARIFLog struct {
+	runs     []*sarifRun
+	analyzer map[*analysis.Analyzer]*sarifRun
+	seen     map[sarifKey]bool
+	content  map[string][]byte // file content, for column conversion
+}
+
+// A sarifKey identifies a diagnostic for de-duplication, since
+// source files may belong to multiple packages (such as foo and foo.test).
+type sarifKey struct {
+	a        *analysis.Analyzer
+	pos, end token.Position
+	message  string
+}
+
+// Add adds the result of analyzer a on package id.
+// The result is either a list of diagnostics or an error.
+// Diagnostics already added at the same location are ignored.
+func (sl *SARIFLog) Add(fset *token.FileSet, id string, a *analysis.Analyzer, diags []analysis.Diagnostic, err error) {
+	if sl.analyzer == nil {
+		sl.analyzer = make(map[*analysis.Analyzer]*sarifRun)
+		sl.seen = make(map[sarifKey]bool)
+		sl.content = make(map[string][]byte)
+	}
+	run, ok := sl.analyzer[a]
+	if !ok {
+		title := strings.Split(a.Doc, "\n\n")[0]
+		run = &sarifRun{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           a.Name,
+				InformationURI: a.URL,
+				Rules: []sarifRule{{
+					ID:               a.Name,
+					ShortDescription: &sarifMessage{Text: title},
+					FullDescription:  &sarifMessage{Text: a.Doc},
+					HelpURI:          a.URL,
+				}},
+			}},
+			Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+			ColumnKind:  "utf16CodeUnits",
+			Results:     []sarifResult{},
+		}
+		sl.analyzer[a] = run
+		sl.runs = append(sl.runs, run)
+	}
+
+	if err != nil {
+		inv := &run.Invocations[0]
+		inv.ExecutionSuccessful = false
+		inv.ToolExecutionNotifications = append(inv.ToolExecutionNotifications, sarifNotification{
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %v", id, err)},
+		})
+		return
+	}
+
+	for _, diag := range diags {
+		posn := fset.Position(diag.Pos)
+		end := fset.Position(cmp.Or(diag.End, diag.Pos))
+		k := sarifKey{a, posn, end, diag.Message}
+		if sl.seen[k] {
+			continue // duplicate
+		}
+		sl.seen[k] = true
+
+		result := sarifResult{
+			RuleID:    a.Name,
+			RuleIndex: 0,
+			Level:     "warning",
+			Message:   sarifMessage{Text: diag.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sl.physicalLocation(posn, end),
+			}},
+		}
+		for i, rel := range diag.Related {
+			result.RelatedLocations = append(result.RelatedLocations, sarifLocation{
+				ID:               i + 1,
+				PhysicalLocation: sl.physicalLocation(fset.Position(rel.Pos), fset.Position(cmp.Or(rel.End, rel.Pos))),
+				Message:          &sarifMessage{Text: rel.Message},
+			})
+		}
+		for _, fix := range diag.SuggestedFixes {
+			// Group the edits by file, preserving order.
+			var changes []sarifArtifactChange
+			index := make(map[string]int)
+			for _, edit := range fix.TextEdits {
+				start := fset.Position(edit.Pos)
+				end := fset.Position(cmp.Or(edit.End, edit.Pos))
+				i, ok := index[start.Filename]
+				if !ok {
+					i = len(changes)
+					index[start.Filename] = i
+					changes = append(changes, sarifArtifactChange{
+						ArtifactLocation: sarifArtifactLocation{URI: fileURI(start.Filename)},
+					})
+				}
+				length := end.Offset - start.Offset
+				changes[i].Replacements = append(changes[i].Replacements, sarifReplacement{
+					DeletedRegion: sarifRegion{
+						ByteOffset: &start.Offset,
+						ByteLength: &length,
+					},
+					InsertedContent: &sarifContent{Text: string(edit.NewText)},
+				})
+			}
+			result.Fixes = append(result.Fixes, sarifFix{
+				Description:     sarifMessage{Text: fix.Message},
+				ArtifactChanges: changes,
+			})
+		}
+		run.Results = append(run.Results, result)
+	}
+}
+
+// physicalLocation returns the SARIF location of the range of source
+// text from posn to end, with columns measured in UTF-16 code units.
+func (sl *SARIFLog) physicalLocation(posn, end token.Position) sarifPhysicalLocation {
+	return sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: fileURI(posn.Filename)},
+		Region: &sarifRegion{
+			StartLine:   posn.Line,
+			StartColumn: sl.utf16Column(posn),
+			EndLine:     end.Line,
+			EndColumn:   sl.utf16Column(end),
+		},
+	}
+}
+
+// utf16Column returns the 1-based column of posn in UTF-16 code units.
+// If the file cannot be read, it returns the byte column.
+func (sl *SARIFLog) utf16Column(posn token.Position) int {
+	content, ok := sl.content[posn.Filename]
+	if !ok {
+		content, _ = os.ReadFile(posn.Filename) // ignore error
+		sl.content[posn.Filename] = content
+	}
+	lineStart := posn.Offset - (posn.Column - 1)
+	if posn.Column < 1 || lineStart < 0 || posn.Offset > len(content) {
+		return posn.Column
+	}
+	col := 1
+	for line := content[lineStart:posn.Offset]; len(line) > 0; {
+		r, size := utf8.DecodeRune(line)
+		col += utf16.RuneLen(r)
+		line = line[size:]
+	}
+	return col
+}
+
+// fileURI returns the file URI for the specified file name.
+func fileURI(filename string) string {
+	path := filepath.ToSlash(filename)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // e.g. Windows drive letter
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// Print writes the log, in JSON form, to out.
+func (sl *SARIFLog) Print(out io.Writer) error {
+	doc := struct {
+		Version string      `json:"version"`
+		Schema  string      `json:"$schema"`
+		Runs    []*sarifRun `json:"runs"`
+	}{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    sl.runs,
+	}
+	if doc.Runs == nil {
+		doc.Runs = []*sarifRun{}
+	}
+	data, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		log.Panicf("internal error: JSON marshaling failed: %v", err)
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// The types below describe the subset of the SARIF 2.1.0 schema
+// used by SARIFLog.
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	ColumnKind  string            `json:"columnKind"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+	FullDescription  *sarifMessage `json:"fullDescription,omitempty"`
+	HelpURI          string        `json:"helpUri,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	RuleIndex        int             `json:"ruleIndex"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int  `json:"startLine,omitempty"`
+	StartColumn int  `json:"startColumn,omitempty"`
+	EndLine     int  `json:"endLine,omitempty"`
+	EndColumn   int  `json:"endColumn,omitempty"`
+	ByteOffset  *int `json:"byteOffset,omitempty"`
+	ByteLength  *int `json:"byteLength,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion   `json:"deletedRegion"`
+	InsertedContent *sarifContent `json:"insertedContent,omitempty"`
+}
+
+type sarifContent struct {
+	Text string `json:"text"`
+}